@@ -42,5 +42,9 @@ func main() {
 
 		AppHost: viper.GetString("host"),
 		AppPort: viper.GetInt("port"),
+
+		SearchSimilarityThreshold: viper.GetFloat64("search_similarity_threshold"),
+
+		ScheduleInterval: viper.GetDuration("schedule_interval"),
 	})
 }
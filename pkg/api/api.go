@@ -3,11 +3,21 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/kott/go-service-example/pkg/db"
+	articlesvc "github.com/kott/go-service-example/pkg/services/articles"
+	articlestore "github.com/kott/go-service-example/pkg/services/articles/store"
 	articles "github.com/kott/go-service-example/pkg/services/articles/transport"
+	authors "github.com/kott/go-service-example/pkg/services/authors/transport"
+	comments "github.com/kott/go-service-example/pkg/services/comments/transport"
 	"github.com/kott/go-service-example/pkg/utils/log"
 	"github.com/kott/go-service-example/pkg/utils/middleware"
 )
@@ -23,9 +33,21 @@ type Config struct {
 
 	AppHost string
 	AppPort int
+
+	// SearchSimilarityThreshold is the minimum trigram similarity, in (0, 1], for an article to be a fuzzy
+	// search hit. Zero, like any value outside that range, uses the pg_trgm default of 0.3.
+	SearchSimilarityThreshold float64
+
+	// ScheduleInterval is how often due articles are published and expired
+	ScheduleInterval time.Duration
 }
 
-// Start initializes the API server, adding the reuired middleware and dependent services
+// shutdownTimeout is how long in-flight requests are given to complete on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// Start initializes the API server, adding the reuired middleware and dependent services. It runs
+// until the process is interrupted or terminated, after which the server and the background
+// workers are shut down gracefully.
 func Start(cfg *Config) {
 	ctx := context.Background()
 	conn, err := db.GetConnection(
@@ -53,15 +75,53 @@ func Start(cfg *Config) {
 
 	router.Use(middleware.PersistContext())
 	router.Use(middleware.RequestLogger())
-	router.Use(middleware.ForceJSON())
+	router.Use(middleware.Negotiate(articles.ExportContentTypes))
+	router.Use(middleware.ForceJSON(articles.ImportContentTypes))
 	router.Use(middleware.Recover())
 
 	router.NoRoute(middleware.NoRoute())
 	router.NoMethod(middleware.NoMethod())
 
-	articles.Activate(router, conn)
+	articles.Activate(router, conn, articles.Config{
+		SimilarityThreshold: cfg.SearchSimilarityThreshold,
+	})
+	authors.Activate(router, conn)
+	comments.Activate(router, conn)
+
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+
+	var workers sync.WaitGroup
+	if conn != nil {
+		scheduler := articlesvc.NewScheduler(articlestore.New(conn), cfg.ScheduleInterval)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			scheduler.Run(workerCtx)
+		}()
+	}
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", cfg.AppHost, cfg.AppPort),
+		Handler: router,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(ctx, err.Error())
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-signals
+	log.Info(ctx, "received %s, shutting down", sig)
 
-	if err := router.Run(fmt.Sprintf("%s:%d", cfg.AppHost, cfg.AppPort)); err != nil {
-		log.Fatal(context.Background(), err.Error())
+	shutdownCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error(ctx, "unable to shut down the server gracefully: %s", err.Error())
 	}
+
+	stopWorkers()
+	workers.Wait()
 }
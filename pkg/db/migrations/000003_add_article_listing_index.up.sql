@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS articles_created_at_id_idx ON articles (created_at, id);
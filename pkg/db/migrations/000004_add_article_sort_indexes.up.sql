@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS articles_updated_at_id_idx ON articles (updated_at, id);
+CREATE INDEX IF NOT EXISTS articles_title_id_idx ON articles (title, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS articles_body_trgm_idx;
+DROP INDEX IF EXISTS articles_title_trgm_idx;
@@ -0,0 +1,4 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS articles_title_trgm_idx ON articles USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS articles_body_trgm_idx ON articles USING gin (body gin_trgm_ops);
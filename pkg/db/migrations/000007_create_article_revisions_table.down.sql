@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS article_revisions;
+
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_pkey;
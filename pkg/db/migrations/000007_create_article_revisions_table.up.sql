@@ -0,0 +1,14 @@
+ALTER TABLE articles ADD CONSTRAINT articles_pkey PRIMARY KEY (id);
+
+CREATE TABLE IF NOT EXISTS article_revisions (
+article_id uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+revision integer NOT NULL,
+title text,
+body text,
+request_id text,
+created_at timestamptz not null,
+PRIMARY KEY (article_id, revision)
+);
+
+INSERT INTO article_revisions (article_id, revision, title, body, request_id, created_at)
+SELECT id, 1, title, body, NULL, updated_at FROM articles;
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS articles_history ON articles;
+
+DROP FUNCTION IF EXISTS record_article_history();
+
+DROP TABLE IF EXISTS article_history;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS article_history (
+article_id uuid NOT NULL,
+valid_from timestamptz NOT NULL,
+valid_to timestamptz,
+data jsonb NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS article_history_article_id_valid_from_idx ON article_history (article_id, valid_from);
+CREATE INDEX IF NOT EXISTS article_history_valid_from_valid_to_idx ON article_history (valid_from, valid_to);
+
+-- every change to an article closes the period of its previous state and, unless the row was
+-- deleted, opens a new period holding the full row as it is now, less the generated search vector,
+-- which reads as of a time never use
+CREATE OR REPLACE FUNCTION record_article_history() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE article_history SET valid_to = now() WHERE article_id = OLD.id AND valid_to IS NULL;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO article_history (article_id, valid_from, data) VALUES (NEW.id, now(), to_jsonb(NEW) - 'search');
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER articles_history
+AFTER INSERT OR UPDATE OR DELETE ON articles
+FOR EACH ROW EXECUTE FUNCTION record_article_history();
+
+-- the earlier states of existing articles are unknown, so their history starts when they were created with the
+-- state they are in now: reads as of a time before their last change are only an approximation of that state
+INSERT INTO article_history (article_id, valid_from, data)
+SELECT id, created_at, to_jsonb(articles) - 'search' FROM articles;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS tags (
+id serial PRIMARY KEY,
+name text NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS article_tags (
+article_id uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+tag_id integer NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+PRIMARY KEY (article_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS article_tags_tag_id_idx ON article_tags (tag_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS authors (
+id uuid NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+name text NOT NULL,
+bio text NOT NULL DEFAULT '',
+created_at timestamptz not null,
+updated_at timestamptz not null
+);
+
+-- authors.Repo.Delete unlinks the articles itself so their version changes, SET NULL only backs it up
+ALTER TABLE articles ADD COLUMN author_id uuid REFERENCES authors (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS articles_author_id_created_at_id_idx ON articles (author_id, created_at, id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS comments_parent_id_idx;
+DROP INDEX IF EXISTS comments_article_id_parent_id_created_at_id_idx;
+
+DROP TABLE IF EXISTS comments;
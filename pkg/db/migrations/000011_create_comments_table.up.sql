@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS comments (
+id uuid NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+article_id uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+parent_id uuid REFERENCES comments (id) ON DELETE CASCADE,
+body text NOT NULL,
+created_at timestamptz not null,
+updated_at timestamptz not null,
+deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS comments_article_id_parent_id_created_at_id_idx ON comments (article_id, parent_id, created_at, id);
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS articles_status_created_at_id_idx;
+
+UPDATE article_history SET data = data - 'status';
+
+ALTER TABLE articles DROP COLUMN IF EXISTS status;
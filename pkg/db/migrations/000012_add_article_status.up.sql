@@ -0,0 +1,9 @@
+-- articles which exist already were public from the moment they were created, so they start out published
+ALTER TABLE articles ADD COLUMN status text NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+ALTER TABLE articles ALTER COLUMN status SET DEFAULT 'draft';
+
+-- earlier states of the articles predate the column, they were published just the same
+UPDATE article_history SET data = data || '{"status": "published"}' WHERE NOT data ? 'status';
+
+CREATE INDEX IF NOT EXISTS articles_status_created_at_id_idx ON articles (status, created_at, id);
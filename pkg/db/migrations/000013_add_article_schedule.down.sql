@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS articles_expire_at_idx;
+DROP INDEX IF EXISTS articles_publish_at_idx;
+
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_schedule_check;
+ALTER TABLE articles DROP COLUMN IF EXISTS expire_at;
+ALTER TABLE articles DROP COLUMN IF EXISTS publish_at;
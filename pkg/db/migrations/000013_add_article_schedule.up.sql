@@ -0,0 +1,8 @@
+ALTER TABLE articles ADD COLUMN publish_at timestamptz;
+ALTER TABLE articles ADD COLUMN expire_at timestamptz;
+ALTER TABLE articles ADD CONSTRAINT articles_schedule_check CHECK (expire_at > publish_at);
+
+-- the scheduler only ever looks for articles in review which are due to be published and published
+-- articles which are due to expire
+CREATE INDEX IF NOT EXISTS articles_publish_at_idx ON articles (publish_at) WHERE status = 'in_review' AND disabled_at IS NULL;
+CREATE INDEX IF NOT EXISTS articles_expire_at_idx ON articles (expire_at) WHERE status = 'published' AND disabled_at IS NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS articles_slug_idx;
+
+UPDATE article_history SET data = data - 'slug';
+
+ALTER TABLE articles DROP COLUMN IF EXISTS slug;
+
+DROP TABLE IF EXISTS article_slugs;
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS article_slugs (
+slug text NOT NULL PRIMARY KEY,
+article_id uuid NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+created_at timestamptz not null
+);
+
+CREATE INDEX IF NOT EXISTS article_slugs_article_id_idx ON article_slugs (article_id);
+
+ALTER TABLE articles ADD COLUMN slug text;
+
+-- existing articles get a slug from their title the same way new ones do (see articles.Slugify), oldest article
+-- first. Only ASCII letters and digits are kept; the letters are lower cased with translate rather than lower, and
+-- matched with explicit ranges rather than [:alnum:], as both of those depend on the locale of the database. Adding
+-- the slug is not a change to the articles themselves, so it is kept out of their history.
+ALTER TABLE articles DISABLE TRIGGER articles_history;
+
+DO $$
+DECLARE
+    a record;
+    base text;
+    candidate text;
+    n integer;
+BEGIN
+    FOR a IN SELECT id, title FROM articles ORDER BY created_at, id LOOP
+        base := rtrim(left(trim(both '-' from regexp_replace(
+            translate(a.title, 'ABCDEFGHIJKLMNOPQRSTUVWXYZ', 'abcdefghijklmnopqrstuvwxyz'), '[^a-z0-9]+', '-', 'g')), 80), '-');
+        IF base = '' THEN
+            base := 'article';
+        END IF;
+
+        candidate := base;
+        n := 1;
+        WHILE candidate IN ('trash', 'search')
+            OR candidate ~ '^[0-9a-f]{32}$'
+            OR candidate ~ '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'
+            OR EXISTS (SELECT 1 FROM article_slugs WHERE slug = candidate) LOOP
+            n := n + 1;
+            candidate := base || '-' || n;
+        END LOOP;
+
+        INSERT INTO article_slugs (slug, article_id, created_at) VALUES (candidate, a.id, now());
+        UPDATE articles SET slug = candidate WHERE id = a.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE articles ENABLE TRIGGER articles_history;
+
+UPDATE article_history SET data = data || jsonb_build_object('slug', coalesce(
+    (SELECT slug FROM articles WHERE id = article_history.article_id), article_history.article_id::text));
+
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS articles_slug_idx ON articles (slug);
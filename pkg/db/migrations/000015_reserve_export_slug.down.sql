@@ -0,0 +1,2 @@
+-- the article which had the slug export keeps its new slug, which is as valid as the old one
+SELECT 1;
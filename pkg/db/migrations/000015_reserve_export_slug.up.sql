@@ -0,0 +1,26 @@
+-- /articles/export shadows the article with the slug export, which moves to the first free export-n slug.
+-- Its old slug stays in article_slugs, so it is not handed out again.
+ALTER TABLE articles DISABLE TRIGGER articles_history;
+
+DO $$
+DECLARE
+    target uuid;
+    candidate text;
+    n integer := 2;
+BEGIN
+    SELECT id INTO target FROM articles WHERE slug = 'export';
+    IF target IS NULL THEN
+        RETURN;
+    END IF;
+
+    candidate := 'export-' || n;
+    WHILE EXISTS (SELECT 1 FROM article_slugs WHERE slug = candidate) LOOP
+        n := n + 1;
+        candidate := 'export-' || n;
+    END LOOP;
+
+    INSERT INTO article_slugs (slug, article_id, created_at) VALUES (candidate, target, now());
+    UPDATE articles SET slug = candidate WHERE id = target;
+END $$;
+
+ALTER TABLE articles ENABLE TRIGGER articles_history;
@@ -41,6 +41,9 @@ const (
 
 	// UnsupportedMediaType ...
 	UnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+
+	// NotAcceptable ...
+	NotAcceptable = "NOT_ACCEPTABLE"
 )
 
 // ErrorCode is the string representation of an HTTP error
@@ -61,6 +64,7 @@ var Descriptions = map[ErrorCode]string{
 	ForbiddenAction:            "The action being performed is forbidden",
 	PreconditionFailed:         "Precondition failed.",
 	UnsupportedMediaType:       "The server does not support the media type transmitted in the request.",
+	NotAcceptable:              "The server cannot respond in any of the media types accepted by the request.",
 }
 
 // AppError application specific error
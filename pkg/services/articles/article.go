@@ -2,57 +2,352 @@ package articles
 
 import (
 	"context"
+	"fmt"
+	"time"
+
+	"github.com/kott/go-service-example/pkg/utils/log"
 )
 
 // Repo defines the DB level interaction of articles
 type Repo interface {
-	Get(ctx context.Context, id string) (Article, error)
-	GetAll(ctx context.Context, limit, offset int) ([]Article, error)
+	Get(ctx context.Context, id string, includeDisabled bool, fields []string) (Article, error)
+	GetAsOf(ctx context.Context, id string, at time.Time, includeDisabled bool) (Article, error)
+	ResolveSlug(ctx context.Context, slug string) (id, canonical string, err error)
+	GetAll(ctx context.Context, p ListParams) ([]Article, error)
+	Count(ctx context.Context, p ListParams) (total int64, estimated bool, err error)
 	Create(ctx context.Context, ar ArticleCreateUpdate) (string, error)
-	Update(ctx context.Context, ar ArticleCreateUpdate, id string) error
+	Update(ctx context.Context, ar ArticleCreateUpdate, id string, version int) error
+	Patch(ctx context.Context, p ArticlePatch, id string, version int) error
+	Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error)
+	GetMany(ctx context.Context, ids []string) ([]Article, error)
+	Export(ctx context.Context, includeUnpublished bool, fn func(Article) error) error
+	Delete(ctx context.Context, id string) error
+	SetStatus(ctx context.Context, id string, t Transition, version int) error
+	PublishDue(ctx context.Context, limit int) (int, error)
+	ExpireDue(ctx context.Context, limit int) (int, error)
+	GetDisabled(ctx context.Context, limit, offset int) ([]Article, error)
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, id string) error
+	Search(ctx context.Context, p SearchParams) ([]Article, error)
+	CountSearch(ctx context.Context, p SearchParams) (total int64, estimated bool, err error)
+	GetRevisions(ctx context.Context, id string, includeUnpublished bool, limit, offset int) ([]Revision, error)
+	GetRevision(ctx context.Context, id string, includeUnpublished bool, revision int) (Revision, error)
+	GetTags(ctx context.Context, limit, offset int) ([]Tag, error)
 }
 
 // Service defines the service level contract that other services
 // outside this package can use to interact with Article resources
 type Service interface {
-	Get(ctx context.Context, id string) (Article, error)
-	GetAll(ctx context.Context, limit, offset int) ([]Article, error)
+	Get(ctx context.Context, id string, includeDisabled bool, fields []string) (Article, error)
+	GetAsOf(ctx context.Context, id string, at time.Time, includeDisabled bool) (Article, error)
+	ResolveSlug(ctx context.Context, slug string) (id, canonical string, err error)
+	Render(ctx context.Context, ar Article) (Article, error)
+	GetAll(ctx context.Context, p ListParams) (Articles, error)
 	Create(ctx context.Context, ar ArticleCreateUpdate) (Article, error)
-	Update(ctx context.Context, ar ArticleCreateUpdate, id string) (Article, error)
+	Update(ctx context.Context, ar ArticleCreateUpdate, id string, version int) (Article, error)
+	Patch(ctx context.Context, p ArticlePatch, id string, version int) (Article, error)
+	Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error)
+	Import(ctx context.Context, r ImportReader, report func(ImportProgress, []ImportFailure)) (ImportProgress, error)
+	Export(ctx context.Context, includeUnpublished bool, fn func(Article) error) error
+	Delete(ctx context.Context, id string) error
+	Transition(ctx context.Context, id string, t Transition, version int) (Article, error)
+	Trash(ctx context.Context, limit, offset int) ([]Article, error)
+	Restore(ctx context.Context, id string) (Article, error)
+	Purge(ctx context.Context, id string) error
+	Search(ctx context.Context, p SearchParams) (Articles, error)
+	Revisions(ctx context.Context, id string, includeUnpublished bool, limit, offset int) (Revisions, error)
+	Revision(ctx context.Context, id string, includeUnpublished bool, revision int) (Revision, error)
+	Revert(ctx context.Context, id string, revision, version int) (Article, error)
+	Diff(ctx context.Context, id string, includeUnpublished bool, from, to int, unit DiffUnit) (Diff, error)
+	Tags(ctx context.Context, limit, offset int) (Tags, error)
 }
 
 type article struct {
-	repo Repo
+	repo     Repo
+	renderer *Renderer
 }
 
 // New Service instance
 func New(repo Repo) Service {
-	return &article{repo}
+	return &article{repo, NewRenderer(DefaultRenderCacheSize)}
+}
+
+// Get sends the request straight to the repo. When fields are given only those are filled in.
+func (s *article) Get(ctx context.Context, id string, includeDisabled bool, fields []string) (Article, error) {
+	return s.repo.Get(ctx, id, includeDisabled, fields)
+}
+
+// GetAsOf sends the request straight to the repo
+func (s *article) GetAsOf(ctx context.Context, id string, at time.Time, includeDisabled bool) (Article, error) {
+	return s.repo.GetAsOf(ctx, id, at, includeDisabled)
+}
+
+// ResolveSlug looks up the id of the article a current or earlier slug belongs to, along with its current slug
+func (s *article) ResolveSlug(ctx context.Context, slug string) (id, canonical string, err error) {
+	return s.repo.ResolveSlug(ctx, slug)
+}
+
+// Render fills in the HTML of the body of the article
+func (s *article) Render(ctx context.Context, ar Article) (Article, error) {
+	body, err := s.renderer.Render(ar)
+	if err != nil {
+		log.Info(ctx, "render article id=%s error: %s", ar.ID, err.Error())
+		return Article{}, ErrArticleRender
+	}
+	ar.BodyHTML = body
+	return ar, nil
 }
 
-// Get sends the request straight to the repo
-func (s *article) Get(ctx context.Context, id string) (Article, error) {
-	return s.repo.Get(ctx, id)
+// GetAll retrieves a page of articles. One more article than requested is read
+// from the repo to find out whether there are more pages (see paginate). The
+// articles of the whole listing are counted as well, unless p.Total is TotalNone.
+func (s *article) GetAll(ctx context.Context, p ListParams) (Articles, error) {
+	limit := p.Limit
+	p.Limit++
+	if len(p.Sort) == 0 {
+		p.Sort = DefaultSort
+	}
+
+	al, err := s.repo.GetAll(ctx, p)
+	if err != nil {
+		return Articles{Articles: al}, err
+	}
+
+	page := paginate(al, limit, p.Offset, p.Cursor,
+		func(ar Article) Cursor { return cursorAfter(ar, p.Sort) },
+		func(ar Article) Cursor { return cursorBefore(ar, p.Sort) })
+
+	if p.Total != TotalNone {
+		total, estimated, err := s.repo.Count(ctx, p)
+		if err != nil {
+			return Articles{Articles: make([]Article, 0)}, err
+		}
+		page.Total, page.TotalEstimated = &total, estimated
+	}
+	return page, nil
 }
 
-// GetAll sends the request straight to the repo
-func (s *article) GetAll(ctx context.Context, limit, offset int) ([]Article, error) {
-	return s.repo.GetAll(ctx, limit, offset)
+// paginate makes a page out of the articles read for it, which are one more than the limit when there are
+// more pages in the direction the page was read in: after the offset or the cursor, or before the cursor
+// when it points back. The extra article is dropped and the cursors to the neighbouring pages are set.
+// A page read after a cursor always has articles before it, and a page read before one always has
+// articles after it, so those cursors are set whenever the page has any articles.
+func paginate(al []Article, limit, offset int, c *Cursor, after, before func(Article) Cursor) Articles {
+	page := Articles{Articles: al, PageInfo: PageInfo{Limit: limit}}
+	if c == nil {
+		page.Offset = &offset
+	}
+
+	back := c != nil && c.Before
+	more := limit > 0 && len(al) > limit
+	if more && back {
+		page.Articles = al[len(al)-limit:]
+	} else if more {
+		page.Articles = al[:limit]
+	}
+	if len(page.Articles) == 0 {
+		return page
+	}
+
+	if more || back {
+		page.NextCursor = EncodeCursor(after(page.Articles[len(page.Articles)-1]))
+	}
+	if c != nil && (more || !back) {
+		page.PrevCursor = EncodeCursor(before(page.Articles[0]))
+	}
+	return page
 }
 
 // Create passes of the created to the repo and retrieves the newly created record
 func (s *article) Create(ctx context.Context, ar ArticleCreateUpdate) (Article, error) {
+	ar.Tags = NormalizeTags(ar.Tags)
 	id, err := s.repo.Create(ctx, ar)
 	if err != nil {
 		return Article{}, err
 	}
-	return s.repo.Get(ctx, id)
+	return s.repo.Get(ctx, id, false, nil)
+}
+
+// Update the requested resource. A non-zero version must match the stored version for the update to be applied.
+func (s *article) Update(ctx context.Context, ar ArticleCreateUpdate, id string, version int) (Article, error) {
+	ar.Tags = NormalizeTags(ar.Tags)
+	if err := s.repo.Update(ctx, ar, id, version); err != nil {
+		return Article{}, err
+	}
+	return s.Get(ctx, id, false, nil)
+}
+
+// Patch only changes the fields which are set on the patch. An empty patch leaves the article (and its version) untouched.
+func (s *article) Patch(ctx context.Context, p ArticlePatch, id string, version int) (Article, error) {
+	if p.Empty() {
+		ar, err := s.Get(ctx, id, false, nil)
+		if err != nil {
+			return Article{}, err
+		}
+		if version != 0 && ar.Version != version {
+			return Article{}, ErrArticleVersionMismatch
+		}
+		return ar, nil
+	}
+
+	if p.Tags != nil {
+		tags := NormalizeTags(*p.Tags)
+		p.Tags = &tags
+	}
+	if err := s.repo.Patch(ctx, p, id, version); err != nil {
+		return Article{}, err
+	}
+	return s.Get(ctx, id, false, nil)
+}
+
+// Batch applies the creates and updates in order. When atomic is set either all of them are applied or,
+// if any fails, none are; otherwise every operation which succeeds is applied. Either way the result of each
+// operation is returned, and the articles of the applied operations are read back all at once.
+func (s *article) Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error) {
+	normalized := make([]BatchOperation, len(ops))
+	for i, op := range ops {
+		op.Article.Tags = NormalizeTags(op.Article.Tags)
+		normalized[i] = op
+	}
+
+	results, err := s.repo.Batch(ctx, normalized, atomic)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, r := range results {
+		if r.Err == nil && r.Article.ID != "" {
+			ids = append(ids, r.Article.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return results, nil
+	}
+
+	al, err := s.repo.GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]Article, len(al))
+	for _, ar := range al {
+		byID[ar.ID] = ar
+	}
+	for i, r := range results {
+		if ar, ok := byID[r.Article.ID]; ok && r.Err == nil {
+			results[i].Article = ar
+		}
+	}
+	return results, nil
+}
+
+// Export sends the request straight to the repo
+func (s *article) Export(ctx context.Context, includeUnpublished bool, fn func(Article) error) error {
+	return s.repo.Export(ctx, includeUnpublished, fn)
+}
+
+// Delete soft-deletes the requested resource by marking it as disabled
+func (s *article) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Transition moves the article along the workflow, which fails with ErrInvalidTransition
+// unless the article is in the status the transition starts from.
+// A non-zero version must match the stored version for the transition to be made.
+func (s *article) Transition(ctx context.Context, id string, t Transition, version int) (Article, error) {
+	if err := s.repo.SetStatus(ctx, id, t, version); err != nil {
+		return Article{}, err
+	}
+	return s.Get(ctx, id, false, nil)
+}
+
+// Trash lists the articles which have been soft-deleted
+func (s *article) Trash(ctx context.Context, limit, offset int) ([]Article, error) {
+	return s.repo.GetDisabled(ctx, limit, offset)
+}
+
+// Restore takes an article out of the trash and retrieves it
+func (s *article) Restore(ctx context.Context, id string) (Article, error) {
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return Article{}, err
+	}
+	return s.Get(ctx, id, false, nil)
+}
+
+// Purge permanently removes an article which is already in the trash
+func (s *article) Purge(ctx context.Context, id string) error {
+	return s.repo.Purge(ctx, id)
+}
+
+// Search finds the articles best matching the query and pages through them the way GetAll does: one more hit
+// than requested is read from the repo to find out whether there are more pages, and the hits
+// are counted as well, unless p.Total is TotalNone.
+func (s *article) Search(ctx context.Context, p SearchParams) (Articles, error) {
+	limit := p.Limit
+	p.Limit++
+
+	hits, err := s.repo.Search(ctx, p)
+	if err != nil {
+		return Articles{Articles: hits}, err
+	}
+
+	page := paginate(hits, limit, p.Offset, p.Cursor, searchCursorAfter, searchCursorBefore)
+
+	if p.Total != TotalNone {
+		total, estimated, err := s.repo.CountSearch(ctx, p)
+		if err != nil {
+			return Articles{Articles: make([]Article, 0)}, err
+		}
+		page.Total, page.TotalEstimated = &total, estimated
+	}
+	return page, nil
+}
+
+// Revisions lists the history of an article, oldest revision first
+func (s *article) Revisions(ctx context.Context, id string, includeUnpublished bool, limit, offset int) (Revisions, error) {
+	revs, err := s.repo.GetRevisions(ctx, id, includeUnpublished, limit, offset)
+	return Revisions{Revisions: revs}, err
+}
+
+// Revision sends the request straight to the repo
+func (s *article) Revision(ctx context.Context, id string, includeUnpublished bool, revision int) (Revision, error) {
+	return s.repo.GetRevision(ctx, id, includeUnpublished, revision)
 }
 
-// Update the requested resource
-func (s *article) Update(ctx context.Context, ar ArticleCreateUpdate, id string) (Article, error) {
-	if err := s.repo.Update(ctx, ar, id); err != nil {
+// Revert sets the title and body of the article back to those of an earlier revision. The revert is
+// an update in its own right, so history is kept and the result is recorded as a new revision.
+// Revisions do not cover tags, so those are left as they are. Like other writes, reverts apply to
+// unpublished articles as well.
+func (s *article) Revert(ctx context.Context, id string, revision, version int) (Article, error) {
+	rev, err := s.repo.GetRevision(ctx, id, true, revision)
+	if err != nil {
 		return Article{}, err
 	}
-	return s.Get(ctx, id)
+	return s.Patch(ctx, ArticlePatch{Title: &rev.Title, Body: &rev.Body}, id, version)
+}
+
+// Diff compares the title and body of two revisions of an article
+func (s *article) Diff(ctx context.Context, id string, includeUnpublished bool, from, to int, unit DiffUnit) (Diff, error) {
+	a, err := s.repo.GetRevision(ctx, id, includeUnpublished, from)
+	if err != nil {
+		return Diff{}, err
+	}
+	b, err := s.repo.GetRevision(ctx, id, includeUnpublished, to)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	return Diff{
+		ArticleID: id,
+		From:      from,
+		To:        to,
+		Title:     UnifiedDiff(a.Title, b.Title, fmt.Sprintf("title@%d", from), fmt.Sprintf("title@%d", to), unit),
+		Body:      UnifiedDiff(a.Body, b.Body, fmt.Sprintf("body@%d", from), fmt.Sprintf("body@%d", to), unit),
+	}, nil
+}
+
+// Tags lists the tags in use by published articles, most used first
+func (s *article) Tags(ctx context.Context, limit, offset int) (Tags, error) {
+	tags, err := s.repo.GetTags(ctx, limit, offset)
+	return Tags{Tags: tags}, err
 }
@@ -12,32 +12,193 @@ import (
 type repoMock struct {
 	GetResult Article
 	GetError  error
+	GetFields []string
+
+	ResolveSlugID        string
+	ResolveSlugCanonical string
+	ResolveSlugError     error
 
 	GetAllResult []Article
 	GetAllError  error
 
+	CountResult    int64
+	CountEstimated bool
+	CountError     error
+
 	CreateResult string
 	CreateError  error
 
 	UpdateError error
+
+	PatchError error
+
+	BatchResult []BatchResult
+	BatchError  error
+
+	GetManyResult []Article
+	GetManyError  error
+
+	ExportResult []Article
+	ExportError  error
+
+	DeleteError error
+
+	SetStatusError error
+
+	// PublishDueResults and ExpireDueResults are handed out one per call, after which nothing is due
+	PublishDueResults []int
+	ExpireDueResults  []int
+	DueError          error
+
+	GetDisabledResult []Article
+	GetDisabledError  error
+
+	RestoreError error
+
+	PurgeError error
+
+	SearchResult []Article
+	SearchError  error
+
+	CountSearchResult int64
+	CountSearchError  error
+
+	GetRevisionsResult []Revision
+	GetRevisionsError  error
+
+	// GetRevisionResult holds the revisions by number, any other revision is not found
+	GetRevisionResult map[int]Revision
+	GetRevisionError  error
+
+	GetTagsResult []Tag
+	GetTagsError  error
+
+	// PatchInput records the last patch passed to the repo
+	PatchInput ArticlePatch
+
+	// BatchInput records the last batch passed to the repo
+	BatchInput []BatchOperation
 }
 
-func (r *repoMock) Get(ctx context.Context, id string) (Article, error) {
+func (r *repoMock) Get(ctx context.Context, id string, includeDisabled bool, fields []string) (Article, error) {
+	r.GetFields = fields
 	return r.GetResult, r.GetError
 }
 
-func (r *repoMock) GetAll(ctx context.Context, limit, offset int) ([]Article, error) {
+func (r *repoMock) GetAsOf(ctx context.Context, id string, at time.Time, includeDisabled bool) (Article, error) {
+	return r.GetResult, r.GetError
+}
+
+func (r *repoMock) ResolveSlug(ctx context.Context, slug string) (string, string, error) {
+	return r.ResolveSlugID, r.ResolveSlugCanonical, r.ResolveSlugError
+}
+
+func (r *repoMock) GetAll(ctx context.Context, p ListParams) ([]Article, error) {
 	return r.GetAllResult, r.GetAllError
 }
 
+func (r *repoMock) Count(ctx context.Context, p ListParams) (int64, bool, error) {
+	return r.CountResult, r.CountEstimated, r.CountError
+}
+
 func (r *repoMock) Create(ctx context.Context, ar ArticleCreateUpdate) (string, error) {
 	return r.CreateResult, r.CreateError
 }
 
-func (r *repoMock) Update(ctx context.Context, ar ArticleCreateUpdate, id string) error {
+func (r *repoMock) Update(ctx context.Context, ar ArticleCreateUpdate, id string, version int) error {
 	return r.UpdateError
 }
 
+func (r *repoMock) Patch(ctx context.Context, p ArticlePatch, id string, version int) error {
+	r.PatchInput = p
+	return r.PatchError
+}
+
+func (r *repoMock) Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error) {
+	r.BatchInput = ops
+	return r.BatchResult, r.BatchError
+}
+
+func (r *repoMock) GetMany(ctx context.Context, ids []string) ([]Article, error) {
+	return r.GetManyResult, r.GetManyError
+}
+
+func (r *repoMock) Export(ctx context.Context, includeUnpublished bool, fn func(Article) error) error {
+	for _, ar := range r.ExportResult {
+		if err := fn(ar); err != nil {
+			return err
+		}
+	}
+	return r.ExportError
+}
+
+func (r *repoMock) Delete(ctx context.Context, id string) error {
+	return r.DeleteError
+}
+
+func (r *repoMock) SetStatus(ctx context.Context, id string, t Transition, version int) error {
+	return r.SetStatusError
+}
+
+func (r *repoMock) PublishDue(ctx context.Context, limit int) (int, error) {
+	return r.due(&r.PublishDueResults)
+}
+
+func (r *repoMock) ExpireDue(ctx context.Context, limit int) (int, error) {
+	return r.due(&r.ExpireDueResults)
+}
+
+func (r *repoMock) due(results *[]int) (int, error) {
+	if r.DueError != nil {
+		return 0, r.DueError
+	}
+	if len(*results) == 0 {
+		return 0, nil
+	}
+	n := (*results)[0]
+	*results = (*results)[1:]
+	return n, nil
+}
+
+func (r *repoMock) GetDisabled(ctx context.Context, limit, offset int) ([]Article, error) {
+	return r.GetDisabledResult, r.GetDisabledError
+}
+
+func (r *repoMock) Restore(ctx context.Context, id string) error {
+	return r.RestoreError
+}
+
+func (r *repoMock) Purge(ctx context.Context, id string) error {
+	return r.PurgeError
+}
+
+func (r *repoMock) Search(ctx context.Context, p SearchParams) ([]Article, error) {
+	return r.SearchResult, r.SearchError
+}
+
+func (r *repoMock) CountSearch(ctx context.Context, p SearchParams) (int64, bool, error) {
+	return r.CountSearchResult, false, r.CountSearchError
+}
+
+func (r *repoMock) GetRevisions(ctx context.Context, id string, includeUnpublished bool, limit, offset int) ([]Revision, error) {
+	return r.GetRevisionsResult, r.GetRevisionsError
+}
+
+func (r *repoMock) GetRevision(ctx context.Context, id string, includeUnpublished bool, revision int) (Revision, error) {
+	if r.GetRevisionError != nil {
+		return Revision{}, r.GetRevisionError
+	}
+	rev, ok := r.GetRevisionResult[revision]
+	if !ok {
+		return Revision{}, ErrRevisionNotFound
+	}
+	return rev, nil
+}
+
+func (r *repoMock) GetTags(ctx context.Context, limit, offset int) ([]Tag, error) {
+	return r.GetTagsResult, r.GetTagsError
+}
+
 func TestServiceGet(t *testing.T) {
 	id := uuid.New().String()
 	tests := map[string]struct {
@@ -66,7 +227,7 @@ func TestServiceGet(t *testing.T) {
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
 			service := New(test.repo)
-			response, err := service.Get(context.Background(), id)
+			response, err := service.Get(context.Background(), id, false, nil)
 
 			assert.Equal(t, test.err, err)
 			assert.Equal(t, test.result.ID, response.ID)
@@ -74,6 +235,153 @@ func TestServiceGet(t *testing.T) {
 	}
 }
 
+func TestServiceRender(t *testing.T) {
+	ar := Article{ID: uuid.New().String(), Body: "**bold**", UpdatedAt: time.Now()}
+
+	service := New(&repoMock{})
+	response, err := service.Render(context.Background(), ar)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ar.Body, response.Body)
+	assert.Equal(t, "<p><strong>bold</strong></p>\n", response.BodyHTML)
+}
+
+func TestServiceGetAll(t *testing.T) {
+	now := time.Now().UTC()
+	a1 := Article{ID: uuid.New().String(), CreatedAt: now}
+	a2 := Article{ID: uuid.New().String(), CreatedAt: now.Add(time.Second)}
+	a3 := Article{ID: uuid.New().String(), CreatedAt: now.Add(2 * time.Second)}
+	zero, offset := 0, 2
+	total, estimate := int64(3), int64(12000)
+
+	tests := map[string]struct {
+		repo   Repo
+		params ListParams
+		result Articles
+		err    error
+	}{
+		"More pages": {
+			repo:   &repoMock{GetAllResult: []Article{a1, a2, a3}, CountResult: 3},
+			params: ListParams{Limit: 2},
+			result: Articles{Articles: []Article{a1, a2}, PageInfo: PageInfo{Total: &total, Limit: 2, Offset: &zero, NextCursor: EncodeCursor(cursorAfter(a2, DefaultSort))}},
+		},
+		"Last page": {
+			repo:   &repoMock{GetAllResult: []Article{a3}, CountResult: 3},
+			params: ListParams{Limit: 2, Offset: 2},
+			result: Articles{Articles: []Article{a3}, PageInfo: PageInfo{Total: &total, Limit: 2, Offset: &offset}},
+		},
+		"Cursor page with an estimated total": {
+			repo:   &repoMock{GetAllResult: []Article{a2, a3}, CountResult: 12000, CountEstimated: true},
+			params: ListParams{Limit: 2, Cursor: &Cursor{}},
+			result: Articles{Articles: []Article{a2, a3}, PageInfo: PageInfo{Total: &estimate, TotalEstimated: true, Limit: 2, PrevCursor: EncodeCursor(cursorBefore(a2, DefaultSort))}},
+		},
+		"Page before a cursor": {
+			repo:   &repoMock{GetAllResult: []Article{a1, a2, a3}, CountResult: 3},
+			params: ListParams{Limit: 2, Cursor: &Cursor{Before: true}},
+			result: Articles{Articles: []Article{a2, a3}, PageInfo: PageInfo{
+				Total: &total, Limit: 2, NextCursor: EncodeCursor(cursorAfter(a3, DefaultSort)), PrevCursor: EncodeCursor(cursorBefore(a2, DefaultSort)),
+			}},
+		},
+		"First page before a cursor": {
+			repo:   &repoMock{GetAllResult: []Article{a1, a2}, CountResult: 3},
+			params: ListParams{Limit: 2, Cursor: &Cursor{Before: true}},
+			result: Articles{Articles: []Article{a1, a2}, PageInfo: PageInfo{Total: &total, Limit: 2, NextCursor: EncodeCursor(cursorAfter(a2, DefaultSort))}},
+		},
+		"Empty cursor page": {
+			repo:   &repoMock{GetAllResult: []Article{}, CountResult: 3},
+			params: ListParams{Limit: 2, Cursor: &Cursor{}},
+			result: Articles{Articles: []Article{}, PageInfo: PageInfo{Total: &total, Limit: 2}},
+		},
+		"Not counted": {
+			repo:   &repoMock{GetAllResult: []Article{a1}, CountError: ErrArticleQuery},
+			params: ListParams{Limit: 2, Total: TotalNone},
+			result: Articles{Articles: []Article{a1}, PageInfo: PageInfo{Limit: 2, Offset: &zero}},
+		},
+		"Query failure": {
+			repo:   &repoMock{GetAllResult: []Article{}, GetAllError: ErrArticleQuery},
+			params: ListParams{Limit: 2},
+			result: Articles{Articles: []Article{}},
+			err:    ErrArticleQuery,
+		},
+		"Count failure": {
+			repo:   &repoMock{GetAllResult: []Article{a1}, CountError: ErrArticleQuery},
+			params: ListParams{Limit: 2},
+			result: Articles{Articles: []Article{}},
+			err:    ErrArticleQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.GetAll(context.Background(), test.params)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
+
+func TestServiceSearch(t *testing.T) {
+	s1, s2 := 0.9, 0.5
+	h1 := Article{ID: uuid.New().String(), Score: &s1}
+	h2 := Article{ID: uuid.New().String(), Score: &s2}
+	offset := 10
+	total := int64(12)
+
+	tests := map[string]struct {
+		repo   Repo
+		params SearchParams
+		result Articles
+		err    error
+	}{
+		"More pages": {
+			repo:   &repoMock{SearchResult: []Article{h1, h2}, CountSearchResult: 12},
+			params: SearchParams{Query: "go", Mode: SearchFullText, Limit: 1, Offset: 10},
+			result: Articles{Articles: []Article{h1}, PageInfo: PageInfo{Total: &total, Limit: 1, Offset: &offset, NextCursor: EncodeCursor(searchCursorAfter(h1))}},
+		},
+		"Last page after a cursor": {
+			repo:   &repoMock{SearchResult: []Article{h2}, CountSearchResult: 12},
+			params: SearchParams{Query: "go", Mode: SearchFullText, Limit: 1, Cursor: &Cursor{}},
+			result: Articles{Articles: []Article{h2}, PageInfo: PageInfo{Total: &total, Limit: 1, PrevCursor: EncodeCursor(searchCursorBefore(h2))}},
+		},
+		"Page before a cursor": {
+			repo:   &repoMock{SearchResult: []Article{h1, h2}, CountSearchResult: 12},
+			params: SearchParams{Query: "go", Mode: SearchFullText, Limit: 1, Cursor: &Cursor{Before: true}},
+			result: Articles{Articles: []Article{h2}, PageInfo: PageInfo{
+				Total: &total, Limit: 1, NextCursor: EncodeCursor(searchCursorAfter(h2)), PrevCursor: EncodeCursor(searchCursorBefore(h2)),
+			}},
+		},
+		"Not counted": {
+			repo:   &repoMock{SearchResult: []Article{h1}, CountSearchError: ErrArticleQuery},
+			params: SearchParams{Query: "go", Mode: SearchFullText, Limit: 1, Offset: 10, Total: TotalNone},
+			result: Articles{Articles: []Article{h1}, PageInfo: PageInfo{Limit: 1, Offset: &offset}},
+		},
+		"Search failure": {
+			repo:   &repoMock{SearchResult: []Article{}, SearchError: ErrArticleQuery},
+			params: SearchParams{Query: "go", Mode: SearchFullText, Limit: 1},
+			result: Articles{Articles: []Article{}},
+			err:    ErrArticleQuery,
+		},
+		"Count failure": {
+			repo:   &repoMock{SearchResult: []Article{h1}, CountSearchError: ErrArticleQuery},
+			params: SearchParams{Query: "go", Mode: SearchFullText, Limit: 1},
+			result: Articles{Articles: []Article{}},
+			err:    ErrArticleQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.Search(context.Background(), test.params)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
+
 func TestServiceCreate(t *testing.T) {
 	id := uuid.New().String()
 	title := "some-title"
@@ -137,3 +445,331 @@ func TestServiceCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestServicePatch(t *testing.T) {
+	id := uuid.New().String()
+	title := "new-title"
+
+	tests := map[string]struct {
+		repo    Repo
+		patch   ArticlePatch
+		version int
+		result  Article
+		err     error
+	}{
+		"Happy path": {
+			repo: &repoMock{
+				GetResult: Article{ID: id, Title: title, Version: 2},
+			},
+			patch:   ArticlePatch{Title: &title},
+			version: 1,
+			result:  Article{ID: id, Title: title, Version: 2},
+		},
+		"Patch failure": {
+			repo: &repoMock{
+				PatchError: ErrArticleVersionMismatch,
+			},
+			patch:   ArticlePatch{Title: &title},
+			version: 1,
+			result:  Article{},
+			err:     ErrArticleVersionMismatch,
+		},
+		"Empty patch": {
+			repo: &repoMock{
+				PatchError: ErrArticleUpdate,
+				GetResult:  Article{ID: id, Version: 2},
+			},
+			version: 2,
+			result:  Article{ID: id, Version: 2},
+		},
+		"Empty patch on stale version": {
+			repo: &repoMock{
+				GetResult: Article{ID: id, Version: 2},
+			},
+			version: 1,
+			result:  Article{},
+			err:     ErrArticleVersionMismatch,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.Patch(context.Background(), test.patch, id, test.version)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
+
+func TestServiceBatch(t *testing.T) {
+	created := Article{ID: uuid.New().String(), Title: "created", Version: 1}
+	updated := Article{ID: uuid.New().String(), Title: "updated", Version: 2}
+	ops := []BatchOperation{
+		{Op: BatchCreate, Article: ArticleCreateUpdate{Title: "created", Tags: []string{" Go "}}},
+		{Op: BatchUpdate, ID: updated.ID, Article: ArticleCreateUpdate{Title: "updated"}},
+		{Op: BatchUpdate, ID: uuid.New().String(), Article: ArticleCreateUpdate{Title: "missing"}},
+	}
+
+	tests := map[string]struct {
+		repo   *repoMock
+		result []BatchResult
+		err    error
+	}{
+		"Happy path": {
+			repo: &repoMock{
+				BatchResult:   []BatchResult{{Article: Article{ID: created.ID}}, {Article: Article{ID: updated.ID}}, {Err: ErrArticleNotFound}},
+				GetManyResult: []Article{updated, created},
+			},
+			result: []BatchResult{{Article: created}, {Article: updated}, {Err: ErrArticleNotFound}},
+		},
+		"Nothing applied": {
+			repo: &repoMock{
+				BatchResult: []BatchResult{{}, {}, {Err: ErrArticleNotFound}},
+			},
+			result: []BatchResult{{}, {}, {Err: ErrArticleNotFound}},
+		},
+		"Batch error": {
+			repo: &repoMock{BatchError: ErrArticleBatch},
+			err:  ErrArticleBatch,
+		},
+		"Read back error": {
+			repo: &repoMock{
+				BatchResult:  []BatchResult{{Article: Article{ID: created.ID}}, {Article: Article{ID: updated.ID}}, {Err: ErrArticleNotFound}},
+				GetManyError: ErrArticleQuery,
+			},
+			err: ErrArticleQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.Batch(context.Background(), ops, true)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+			assert.Equal(t, []string{"go"}, test.repo.BatchInput[0].Article.Tags)
+			assert.Equal(t, []string{" Go "}, ops[0].Article.Tags)
+		})
+	}
+}
+
+func TestServiceDelete(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		repo Repo
+		err  error
+	}{
+		"Happy path": {
+			repo: &repoMock{DeleteError: nil},
+			err:  nil,
+		},
+		"Already disabled or missing": {
+			repo: &repoMock{DeleteError: ErrArticleNotFound},
+			err:  ErrArticleNotFound,
+		},
+		"Delete failure": {
+			repo: &repoMock{DeleteError: ErrArticleDelete},
+			err:  ErrArticleDelete,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			err := service.Delete(context.Background(), id)
+
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestServiceRestore(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		repo   Repo
+		result Article
+		err    error
+	}{
+		"Happy path": {
+			repo: &repoMock{
+				RestoreError: nil,
+				GetResult:    Article{ID: id},
+			},
+			result: Article{ID: id},
+			err:    nil,
+		},
+		"Not in trash": {
+			repo: &repoMock{
+				RestoreError: ErrArticleNotDisabled,
+				GetResult:    Article{ID: id},
+			},
+			result: Article{},
+			err:    ErrArticleNotDisabled,
+		},
+		"Restore failure": {
+			repo: &repoMock{
+				RestoreError: ErrArticleRestore,
+			},
+			result: Article{},
+			err:    ErrArticleRestore,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.Restore(context.Background(), id)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
+
+func TestServiceRevisions(t *testing.T) {
+	id := uuid.New().String()
+	revs := []Revision{
+		{ArticleID: id, Revision: 1, Title: "title", Body: "body"},
+		{ArticleID: id, Revision: 2, Title: "title", Body: "new body"},
+	}
+	tests := map[string]struct {
+		repo   Repo
+		result Revisions
+		err    error
+	}{
+		"Happy path": {
+			repo:   &repoMock{GetRevisionsResult: revs},
+			result: Revisions{Revisions: revs},
+			err:    nil,
+		},
+		"Article not found": {
+			repo:   &repoMock{GetRevisionsResult: []Revision{}, GetRevisionsError: ErrArticleNotFound},
+			result: Revisions{Revisions: []Revision{}},
+			err:    ErrArticleNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.Revisions(context.Background(), id, false, 25, 0)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
+
+func TestServiceRevert(t *testing.T) {
+	id := uuid.New().String()
+	revs := map[int]Revision{1: {ArticleID: id, Revision: 1, Title: "old title", Body: "old body"}}
+	tests := map[string]struct {
+		repo     Repo
+		revision int
+		result   Article
+		err      error
+	}{
+		"Happy path": {
+			repo: &repoMock{
+				GetRevisionResult: revs,
+				GetResult:         Article{ID: id, Title: "old title", Body: "old body", Version: 4},
+			},
+			revision: 1,
+			result:   Article{ID: id, Title: "old title", Body: "old body", Version: 4},
+			err:      nil,
+		},
+		"Revision not found": {
+			repo:     &repoMock{GetRevisionResult: revs},
+			revision: 2,
+			result:   Article{},
+			err:      ErrRevisionNotFound,
+		},
+		"Stale version": {
+			repo: &repoMock{
+				GetRevisionResult: revs,
+				PatchError:        ErrArticleVersionMismatch,
+			},
+			revision: 1,
+			result:   Article{},
+			err:      ErrArticleVersionMismatch,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.Revert(context.Background(), id, test.revision, 3)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
+
+func TestServiceDiff(t *testing.T) {
+	id := uuid.New().String()
+	repo := &repoMock{GetRevisionResult: map[int]Revision{
+		1: {ArticleID: id, Revision: 1, Title: "title", Body: "one\ntwo\nthree\n"},
+		2: {ArticleID: id, Revision: 2, Title: "title", Body: "one\n2\nthree\n"},
+	}}
+
+	service := New(repo)
+	response, err := service.Diff(context.Background(), id, false, 1, 2, DiffLines)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Diff{
+		ArticleID: id,
+		From:      1,
+		To:        2,
+		Title:     "",
+		Body:      "--- body@1\n+++ body@2\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n",
+	}, response)
+
+	_, err = service.Diff(context.Background(), id, false, 1, 3, DiffLines)
+	assert.Equal(t, ErrRevisionNotFound, err)
+}
+
+func TestServicePatchNormalizesTags(t *testing.T) {
+	id := uuid.New().String()
+	tags := []string{"Go", "db", "go"}
+	repo := &repoMock{GetResult: Article{ID: id, Tags: []string{"db", "go"}}}
+
+	service := New(repo)
+	_, err := service.Patch(context.Background(), ArticlePatch{Tags: &tags}, id, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &[]string{"db", "go"}, repo.PatchInput.Tags)
+}
+
+func TestServiceTransition(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		repo   Repo
+		result Article
+		err    error
+	}{
+		"Happy path": {
+			repo:   &repoMock{GetResult: Article{ID: id, Status: StatusPublished, Version: 4}},
+			result: Article{ID: id, Status: StatusPublished, Version: 4},
+			err:    nil,
+		},
+		"Invalid transition": {
+			repo:   &repoMock{SetStatusError: ErrInvalidTransition},
+			result: Article{},
+			err:    ErrInvalidTransition,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.Transition(context.Background(), id, Transitions["publish"], 3)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
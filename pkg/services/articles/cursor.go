@@ -0,0 +1,94 @@
+package articles
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+)
+
+// rankSort marks the cursors of search results. Hits are ordered by their score, best match
+// first, and then by id, so these cursors hold the score of the last hit on a page.
+const rankSort = "rank"
+
+// Cursor is the keyset position of the last article on a page. Listings are ordered by
+// their sort fields and then by id, so the next page starts right after these values.
+// A cursor marked Before holds the position of the first article on a page instead,
+// and the previous page ends right before these values.
+type Cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	ID     string   `json:"i"`
+	Before bool     `json:"b,omitempty"`
+}
+
+// cursorAfter is the position of the given article in a listing with the given ordering
+func cursorAfter(ar Article, sort []SortField) Cursor {
+	c := Cursor{Sort: FormatSort(sort), ID: ar.ID}
+	for _, s := range sort {
+		c.Values = append(c.Values, sortValue(ar, s.Field))
+	}
+	return c
+}
+
+// cursorBefore is the position of the given article in a listing with the given ordering, pointing back
+func cursorBefore(ar Article, sort []SortField) Cursor {
+	c := cursorAfter(ar, sort)
+	c.Before = true
+	return c
+}
+
+// searchCursorAfter is the position of the given hit in search results
+func searchCursorAfter(ar Article) Cursor {
+	var score float64
+	if ar.Score != nil {
+		score = *ar.Score
+	}
+	return Cursor{Sort: rankSort, Values: []string{strconv.FormatFloat(score, 'g', -1, 64)}, ID: ar.ID}
+}
+
+// searchCursorBefore is the position of the given hit in search results, pointing back
+func searchCursorBefore(ar Article) Cursor {
+	c := searchCursorAfter(ar)
+	c.Before = true
+	return c
+}
+
+// EncodeCursor turns a cursor into the opaque token handed to clients
+func EncodeCursor(c Cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a token produced by EncodeCursor for a listing with the given ordering
+func DecodeCursor(token string, sort []SortField) (Cursor, error) {
+	c, err := decodeCursor(token)
+	if err != nil || c.Sort != FormatSort(sort) || len(c.Values) != len(sort) {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// DecodeSearchCursor parses a token handed out with search results
+func DecodeSearchCursor(token string) (Cursor, error) {
+	c, err := decodeCursor(token)
+	if err != nil || c.Sort != rankSort || len(c.Values) != 1 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if _, err := strconv.ParseFloat(c.Values[0], 64); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+func decodeCursor(token string) (Cursor, error) {
+	var c Cursor
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
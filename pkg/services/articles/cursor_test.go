@@ -0,0 +1,70 @@
+package articles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	sort := []SortField{{Field: SortUpdatedAt, Desc: true}, {Field: SortTitle}}
+	ar := Article{
+		ID:        uuid.New().String(),
+		Title:     "some-title",
+		UpdatedAt: time.Date(2021, 4, 1, 12, 30, 15, 123456000, time.UTC),
+	}
+	c := cursorAfter(ar, sort)
+
+	decoded, err := DecodeCursor(EncodeCursor(c), sort)
+	assert.NoError(t, err)
+	assert.Equal(t, Cursor{Sort: "-updatedAt,title", Values: []string{"2021-04-01T12:30:15.123456Z", "some-title"}, ID: ar.ID}, decoded)
+}
+
+func TestCursorBeforeRoundTrip(t *testing.T) {
+	ar := Article{ID: uuid.New().String(), CreatedAt: time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC)}
+
+	decoded, err := DecodeCursor(EncodeCursor(cursorBefore(ar, DefaultSort)), DefaultSort)
+	assert.NoError(t, err)
+	assert.Equal(t, Cursor{Sort: "createdAt", Values: []string{"2021-04-01T12:00:00Z"}, ID: ar.ID, Before: true}, decoded)
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	c := EncodeCursor(cursorAfter(Article{ID: uuid.New().String()}, DefaultSort))
+	tests := map[string]struct {
+		token string
+		sort  []SortField
+	}{
+		"Not base64":      {token: "!!!", sort: DefaultSort},
+		"Not JSON":        {token: "bm90LWpzb24", sort: DefaultSort},
+		"Missing members": {token: EncodeCursor(Cursor{}), sort: DefaultSort},
+		"Different sort":  {token: c, sort: []SortField{{Field: SortTitle}}},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := DecodeCursor(test.token, test.sort)
+			assert.Equal(t, ErrInvalidCursor, err)
+		})
+	}
+}
+
+func TestSearchCursor(t *testing.T) {
+	score := 0.0607927
+	ar := Article{ID: uuid.New().String(), Score: &score}
+	token := EncodeCursor(searchCursorAfter(ar))
+
+	c, err := DecodeSearchCursor(token)
+	assert.NoError(t, err)
+	assert.Equal(t, Cursor{Sort: rankSort, Values: []string{"0.0607927"}, ID: ar.ID}, c)
+
+	_, err = DecodeCursor(token, DefaultSort)
+	assert.Equal(t, ErrInvalidCursor, err)
+
+	_, err = DecodeSearchCursor(EncodeCursor(cursorAfter(Article{ID: uuid.New().String()}, DefaultSort)))
+	assert.Equal(t, ErrInvalidCursor, err)
+
+	_, err = DecodeSearchCursor(EncodeCursor(Cursor{Sort: rankSort, Values: []string{"high"}, ID: ar.ID}))
+	assert.Equal(t, ErrInvalidCursor, err)
+}
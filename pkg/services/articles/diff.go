@@ -0,0 +1,195 @@
+package articles
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffUnit selects the granularity at which two revisions are compared
+type DiffUnit int
+
+const (
+	// DiffLines compares texts line by line
+	DiffLines DiffUnit = iota
+	// DiffWords compares texts word by word, ignoring changes in whitespace
+	DiffWords
+)
+
+// diffContext is the number of unchanged tokens shown around every change
+const diffContext = 3
+
+type editOp byte
+
+const (
+	opEqual  editOp = ' '
+	opDelete editOp = '-'
+	opInsert editOp = '+'
+)
+
+// edit is a single step of the script which turns one token sequence into another
+type edit struct {
+	op   editOp
+	text string
+}
+
+// UnifiedDiff compares a and b at the given granularity and renders the differences in unified
+// diff format, each token on its own line. Identical texts result in an empty string.
+func UnifiedDiff(a, b, fromName, toName string, unit DiffUnit) string {
+	edits := diffTokens(tokenize(a, unit), tokenize(b, unit))
+	return formatUnified(edits, fromName, toName, diffContext)
+}
+
+func tokenize(s string, unit DiffUnit) []string {
+	if unit == DiffWords {
+		return strings.Fields(s)
+	}
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffTokens finds the shortest edit script between a and b using the greedy algorithm by Myers.
+// The state of the frontier is kept for every edit distance so the path can be traced back.
+func diffTokens(a, b []string) []edit {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	edits := make([]edit, 0, max)
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		prevK := k - 1
+		if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[max+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{opEqual, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{opInsert, b[y-1]})
+			} else {
+				edits = append(edits, edit{opDelete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// formatUnified renders the edit script as unified diff hunks, merging changes which are
+// less than two contexts apart into the same hunk.
+func formatUnified(edits []edit, fromName, toName string, context int) string {
+	var changes []int
+	for i, e := range edits {
+		if e.op != opEqual {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	for i := 0; i < len(changes); {
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*context {
+			j++
+		}
+
+		start := changes[i] - context
+		if start < 0 {
+			start = 0
+		}
+		end := changes[j] + context + 1
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		writeHunk(&sb, edits, start, end)
+		i = j + 1
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, edits []edit, start, end int) {
+	var aBefore, bBefore int
+	for _, e := range edits[:start] {
+		if e.op != opInsert {
+			aBefore++
+		}
+		if e.op != opDelete {
+			bBefore++
+		}
+	}
+
+	var aCount, bCount int
+	for _, e := range edits[start:end] {
+		if e.op != opInsert {
+			aCount++
+		}
+		if e.op != opDelete {
+			bCount++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(aBefore, aCount), hunkRange(bBefore, bCount))
+	for _, e := range edits[start:end] {
+		sb.WriteByte(byte(e.op))
+		sb.WriteString(e.text)
+		sb.WriteByte('\n')
+	}
+}
+
+// hunkRange formats a range the way diff -u does: an empty range starts at the line before it
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -0,0 +1,76 @@
+package articles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := map[string]struct {
+		a, b   string
+		unit   DiffUnit
+		expect string
+	}{
+		"Identical": {
+			a:      "one\ntwo\n",
+			b:      "one\ntwo\n",
+			unit:   DiffLines,
+			expect: "",
+		},
+		"Both empty": {
+			a:      "",
+			b:      "",
+			unit:   DiffLines,
+			expect: "",
+		},
+		"From empty": {
+			a:      "",
+			b:      "one\ntwo",
+			unit:   DiffLines,
+			expect: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+one\n+two\n",
+		},
+		"To empty": {
+			a:      "one",
+			b:      "",
+			unit:   DiffLines,
+			expect: "--- a\n+++ b\n@@ -1 +0,0 @@\n-one\n",
+		},
+		"Changed line with context": {
+			a:      "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:      "1\n2\n3\n4\nfive\n6\n7\n8\n",
+			unit:   DiffLines,
+			expect: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		"Separate hunks": {
+			a:      "a\n1\n2\n3\n4\n5\n6\n7\nb\n",
+			b:      "A\n1\n2\n3\n4\n5\n6\n7\nB\n",
+			unit:   DiffLines,
+			expect: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-b\n+B\n",
+		},
+		"Nearby changes share a hunk": {
+			a:      "a\n1\n2\nb\n",
+			b:      "A\n1\n2\nB\n",
+			unit:   DiffLines,
+			expect: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n-b\n+B\n",
+		},
+		"Words": {
+			a:      "the quick brown fox",
+			b:      "the  slow brown\nfox jumps",
+			unit:   DiffWords,
+			expect: "--- a\n+++ b\n@@ -1,4 +1,5 @@\n the\n-quick\n+slow\n brown\n fox\n+jumps\n",
+		},
+		"Whitespace only change of words": {
+			a:      "the quick fox",
+			b:      "the\nquick  fox",
+			unit:   DiffWords,
+			expect: "",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, test.expect, UnifiedDiff(test.a, test.b, "a", "b", test.unit))
+		})
+	}
+}
@@ -11,9 +11,48 @@ var (
 	// ErrArticleQuery ...
 	ErrArticleQuery = errors.New("requested articles could not be retrieved base on the given criteria")
 
+	// ErrInvalidCursor ...
+	ErrInvalidCursor = errors.New("cursor is malformed or does not match the requested sort")
+
 	// ErrArticleCreate ...
 	ErrArticleCreate = errors.New("article could not be created")
 
 	// ErrArticleUpdate ...
 	ErrArticleUpdate = errors.New("article could not be updated")
+
+	// ErrArticleVersionMismatch ...
+	ErrArticleVersionMismatch = errors.New("article has been modified since the requested version")
+
+	// ErrArticleDelete ...
+	ErrArticleDelete = errors.New("article could not be deleted")
+
+	// ErrArticleNotDisabled ...
+	ErrArticleNotDisabled = errors.New("article must be in the trash to be restored or permanently deleted")
+
+	// ErrArticleRestore ...
+	ErrArticleRestore = errors.New("article could not be restored")
+
+	// ErrArticlePurge ...
+	ErrArticlePurge = errors.New("article could not be permanently deleted")
+
+	// ErrArticleAuthorNotFound ...
+	ErrArticleAuthorNotFound = errors.New("author of the article could not be found")
+
+	// ErrInvalidTransition ...
+	ErrInvalidTransition = errors.New("article is not in the status this transition starts from")
+
+	// ErrInvalidSchedule ...
+	ErrInvalidSchedule = errors.New("article must expire after it is published")
+
+	// ErrArticleBatch ...
+	ErrArticleBatch = errors.New("batch of articles could not be applied")
+
+	// ErrImportRead ...
+	ErrImportRead = errors.New("import could not be read to the end")
+
+	// ErrArticleRender ...
+	ErrArticleRender = errors.New("article body could not be rendered")
+
+	// ErrRevisionNotFound ...
+	ErrRevisionNotFound = errors.New("requested revision could not be found")
 )
@@ -0,0 +1,21 @@
+package articles
+
+// Fields is the whitelist of fields an article can be narrowed down to, named as they appear in the JSON
+// representation. BodyHTML and Author are only present when they are asked for with format and expand.
+var Fields = map[string]bool{
+	"id":         true,
+	"slug":       true,
+	"title":      true,
+	"body":       true,
+	"bodyHtml":   true,
+	"createdAt":  true,
+	"updatedAt":  true,
+	"disabledAt": true,
+	"version":    true,
+	"status":     true,
+	"publishAt":  true,
+	"expireAt":   true,
+	"tags":       true,
+	"authorId":   true,
+	"author":     true,
+}
@@ -0,0 +1,97 @@
+package articles
+
+import (
+	"context"
+	"io"
+)
+
+// importBatchSize is the number of articles inserted per transaction during an import
+const importBatchSize = 500
+
+// ImportRecord is an article read from an import, or the reason the line it was read from cannot be imported
+type ImportRecord struct {
+	Line    int
+	Article ArticleCreateUpdate
+	Err     error
+}
+
+// ImportReader reads the articles of an import one at a time. It returns io.EOF after the last record,
+// any other error means the rest of the import cannot be read; the Line of the record is still set then.
+type ImportReader interface {
+	Read() (ImportRecord, error)
+}
+
+// ImportFailure is a line of an import which was not imported, and why
+type ImportFailure struct {
+	Line int
+	Err  error
+}
+
+// ImportProgress counts the lines read, the articles imported and the lines which failed so far
+type ImportProgress struct {
+	Lines    int `json:"lines"`
+	Imported int `json:"imported"`
+	Failed   int `json:"failed"`
+}
+
+// Import creates the articles read from r in batches, each batch in a transaction of its own. Lines which cannot
+// be read or imported are skipped. After every batch the progress so far and the failures of the batch are
+// reported, so neither the import nor its failures have to be held in memory. When r fails the lines read up to
+// that point are still imported before ErrImportRead is returned.
+func (s *article) Import(ctx context.Context, r ImportReader, report func(ImportProgress, []ImportFailure)) (ImportProgress, error) {
+	var p ImportProgress
+	var failures []ImportFailure
+	ops := make([]BatchOperation, 0, importBatchSize)
+	lines := make([]int, 0, importBatchSize)
+
+	flush := func() error {
+		if len(ops) > 0 {
+			results, err := s.repo.Batch(ctx, ops, false)
+			if err != nil {
+				return err
+			}
+			for i, res := range results {
+				if res.Err != nil {
+					failures = append(failures, ImportFailure{Line: lines[i], Err: res.Err})
+					continue
+				}
+				p.Imported++
+			}
+		}
+
+		p.Failed += len(failures)
+		report(p, failures)
+		ops, lines, failures = ops[:0], lines[:0], nil
+		return nil
+	}
+
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return p, flush()
+		}
+		if err != nil {
+			p.Lines++
+			failures = append(failures, ImportFailure{Line: rec.Line, Err: err})
+			if err := flush(); err != nil {
+				return p, err
+			}
+			return p, ErrImportRead
+		}
+
+		p.Lines++
+		if rec.Err != nil {
+			failures = append(failures, ImportFailure{Line: rec.Line, Err: rec.Err})
+		} else {
+			rec.Article.Tags = NormalizeTags(rec.Article.Tags)
+			ops = append(ops, BatchOperation{Op: BatchCreate, Article: rec.Article})
+			lines = append(lines, rec.Line)
+		}
+
+		if len(ops)+len(failures) == importBatchSize {
+			if err := flush(); err != nil {
+				return p, err
+			}
+		}
+	}
+}
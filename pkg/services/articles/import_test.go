@@ -0,0 +1,85 @@
+package articles
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sliceReader hands out the records and then fails with err, or io.EOF when err is nil
+type sliceReader struct {
+	records []ImportRecord
+	err     error
+}
+
+func (r *sliceReader) Read() (ImportRecord, error) {
+	if len(r.records) == 0 {
+		if r.err != nil {
+			return ImportRecord{Line: 99}, r.err
+		}
+		return ImportRecord{}, io.EOF
+	}
+	rec := r.records[0]
+	r.records = r.records[1:]
+	return rec, nil
+}
+
+func TestServiceImport(t *testing.T) {
+	unreadable := errors.New("line is not an article")
+	records := []ImportRecord{
+		{Line: 1, Article: ArticleCreateUpdate{Title: "first", Tags: []string{"Go"}}},
+		{Line: 2, Err: unreadable},
+		{Line: 4, Article: ArticleCreateUpdate{Title: "second"}},
+	}
+
+	tests := map[string]struct {
+		repo     *repoMock
+		readErr  error
+		progress ImportProgress
+		failures []ImportFailure
+		err      error
+	}{
+		"Happy path": {
+			repo:     &repoMock{BatchResult: []BatchResult{{Article: Article{ID: "a"}}, {Err: ErrArticleAuthorNotFound}}},
+			progress: ImportProgress{Lines: 3, Imported: 1, Failed: 2},
+			failures: []ImportFailure{{Line: 2, Err: unreadable}, {Line: 4, Err: ErrArticleAuthorNotFound}},
+		},
+		"Read error": {
+			repo:     &repoMock{BatchResult: []BatchResult{{Article: Article{ID: "a"}}, {Article: Article{ID: "b"}}}},
+			readErr:  io.ErrUnexpectedEOF,
+			progress: ImportProgress{Lines: 4, Imported: 2, Failed: 2},
+			failures: []ImportFailure{{Line: 2, Err: unreadable}, {Line: 99, Err: io.ErrUnexpectedEOF}},
+			err:      ErrImportRead,
+		},
+		"Batch error": {
+			repo:     &repoMock{BatchError: ErrArticleBatch},
+			progress: ImportProgress{Lines: 3},
+			err:      ErrArticleBatch,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var reports []ImportProgress
+			var failures []ImportFailure
+
+			service := New(test.repo)
+			progress, err := service.Import(context.Background(), &sliceReader{records: append([]ImportRecord(nil), records...), err: test.readErr},
+				func(p ImportProgress, f []ImportFailure) {
+					reports = append(reports, p)
+					failures = append(failures, f...)
+				})
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.progress, progress)
+			assert.Equal(t, test.failures, failures)
+			if test.err != ErrArticleBatch {
+				assert.Equal(t, []ImportProgress{test.progress}, reports)
+				assert.Equal(t, []string{"go"}, test.repo.BatchInput[0].Article.Tags)
+			}
+		})
+	}
+}
@@ -1,28 +1,221 @@
 package articles
 
-import "time"
+import (
+	"time"
+
+	"github.com/kott/go-service-example/pkg/services/authors"
+)
 
 // Article is the nominal object used for interacting with articles.
 // This represents what is stored in the database.
 type Article struct {
 	ID string `json:"id"`
 
+	// Slug identifies the article in URLs. It is derived from the title, so it changes along with
+	// the title; earlier slugs keep resolving to the article.
+	Slug string `json:"slug"`
+
 	Title string `json:"title"`
 	Body  string `json:"body"`
 
+	// BodyHTML is the Markdown body rendered to sanitized HTML, it is only filled in when it is asked for.
+	BodyHTML string `json:"bodyHtml,omitempty"`
+
 	CreatedAt  time.Time  `json:"createdAt"`
 	UpdatedAt  time.Time  `json:"updatedAt"`
 	DisabledAt *time.Time `json:"disabledAt,omitempty"`
+
+	// Version is incremented on every change to the row and is used for optimistic concurrency.
+	Version int `json:"version"`
+
+	// Status is where the article is in the editorial workflow, it only changes through Transitions.
+	Status Status `json:"status"`
+
+	// PublishAt and ExpireAt schedule the article to be published (once it is in review)
+	// and archived (once it is published) by the Scheduler.
+	PublishAt *time.Time `json:"publishAt,omitempty"`
+	ExpireAt  *time.Time `json:"expireAt,omitempty"`
+
+	Tags []string `json:"tags"`
+
+	// AuthorID links the article to its author, if it has one. The Author summary
+	// is only filled in when it is asked for.
+	AuthorID *string          `json:"authorId,omitempty"`
+	Author   *authors.Summary `json:"author,omitempty"`
+
+	// Score and Snippet are only filled in on search hits: how well the article matched the query and, for
+	// full-text searches, fragments of its title and body with the matching keywords highlighted.
+	Score   *float64 `json:"score,omitempty"`
+	Snippet *Snippet `json:"snippet,omitempty"`
 }
 
-// Articles is used to present a list of articles in a JSON response.
+// Articles is used to present a list of articles, or of search hits, in a JSON
+// response, along with where the page sits within the listing.
 type Articles struct {
 	Articles []Article `json:"articles"`
+	PageInfo
+}
+
+// PageInfo tells where a page sits within a listing. Total counts the articles of the whole listing,
+// unless counting was turned off; TotalEstimated is set when it is the estimate of the query planner.
+// Offset is only set on pages picked by offset. NextCursor is set when there are more articles after
+// this page; PrevCursor is set on pages picked by cursor when there are more articles before it (pages
+// picked by offset lead back by offset).
+type PageInfo struct {
+	Total          *int64 `json:"total,omitempty"`
+	TotalEstimated bool   `json:"totalEstimated,omitempty"`
+	Limit          int    `json:"limit"`
+	Offset         *int   `json:"offset,omitempty"`
+	NextCursor     string `json:"nextCursor,omitempty"`
+	PrevCursor     string `json:"prevCursor,omitempty"`
+}
+
+// ListParams narrows down and pages through a listing of articles. When a Cursor
+// is given the page starts right after it, or ends right before it when the cursor
+// points Before, and Offset is not used. An empty Sort
+// means DefaultSort. A non-nil AsOf lists the articles as they were at that time.
+// When Tags are given only articles carrying any or all of them, depending on
+// TagMatch, are listed. A non-empty AuthorID only lists the articles of that author.
+// When Statuses are given only articles in one of them are listed. When Fields
+// are given only those are read, along with the id, version and updatedAt of
+// every article and the fields it is sorted by; the rest are left empty. Total
+// selects how the articles of the whole listing are counted.
+type ListParams struct {
+	Limit           int
+	Offset          int
+	Cursor          *Cursor
+	Sort            []SortField
+	IncludeDisabled bool
+	AsOf            *time.Time
+	Tags            []string
+	TagMatch        TagMatch
+	AuthorID        string
+	Statuses        []Status
+	Fields          []string
+	Total           TotalCount
 }
 
 // ArticleCreateUpdate is the request body that is
 // accepted for create and updates to articles.
+// The tags, author and schedule replace the ones the article had before.
 type ArticleCreateUpdate struct {
-	Title string `json:"title" binding:"required"`
-	Body  string `json:"body" binding:"required"`
+	Title     string     `json:"title" binding:"required"`
+	Body      string     `json:"body" binding:"required"`
+	Tags      []string   `json:"tags" binding:"omitempty,dive,required,max=50"`
+	AuthorID  *string    `json:"authorId" binding:"omitempty,uuid"`
+	PublishAt *time.Time `json:"publishAt"`
+	ExpireAt  *time.Time `json:"expireAt"`
+}
+
+// BatchOp is the kind of change a BatchOperation makes
+type BatchOp string
+
+const (
+	// BatchCreate creates a new article
+	BatchCreate BatchOp = "create"
+	// BatchUpdate updates the article with the ID of the operation, like a PUT would
+	BatchUpdate BatchOp = "update"
+)
+
+// BatchOperation is one of the creates or updates of a batch. A non-zero Version must match
+// the stored version of the article for an update to be applied.
+type BatchOperation struct {
+	Op      BatchOp             `json:"op" binding:"required,oneof=create update"`
+	ID      string              `json:"id" binding:"omitempty,uuid"`
+	Version int                 `json:"version" binding:"min=0"`
+	Article ArticleCreateUpdate `json:"article"`
+}
+
+// BatchResult is the outcome of the BatchOperation at the same index: either the
+// article as it is after the operation, or the error the operation failed with.
+type BatchResult struct {
+	Article Article
+	Err     error
+}
+
+// ArticlePatch is a partial update to an article. Only the fields
+// which are set are changed, everything else is left as it is.
+// PublishAt and ExpireAt are cleared when they are set to a nil time.
+type ArticlePatch struct {
+	Title     *string     `json:"title,omitempty"`
+	Body      *string     `json:"body,omitempty"`
+	Tags      *[]string   `json:"tags,omitempty"`
+	PublishAt **time.Time `json:"publishAt,omitempty"`
+	ExpireAt  **time.Time `json:"expireAt,omitempty"`
+}
+
+// Empty is true when the patch does not change anything
+func (p ArticlePatch) Empty() bool {
+	return p.Title == nil && p.Body == nil && p.Tags == nil && p.PublishAt == nil && p.ExpireAt == nil
+}
+
+// SearchMode selects how the query of a search is matched against articles
+type SearchMode int
+
+const (
+	// SearchFuzzy matches titles (and bodies, when IncludeBody is set) by trigram similarity
+	SearchFuzzy SearchMode = iota
+	// SearchFullText matches the keywords of the query against titles and bodies
+	SearchFullText
+)
+
+// SearchParams describes a search for articles. For fuzzy searches, articles whose title (or body,
+// when IncludeBody is set) is at least Threshold similar to Query are returned. Hits are paged the
+// way ListParams pages a listing, best match first: when a Cursor is given the page starts right
+// after it (or ends right before it) and Offset is not used. Total selects how the hits are counted.
+type SearchParams struct {
+	Query       string
+	Mode        SearchMode
+	IncludeBody bool
+	Threshold   float64
+	Limit       int
+	Offset      int
+	Cursor      *Cursor
+	Total       TotalCount
+}
+
+// Snippet holds fragments of the title and body of a full-text search hit with the matching keywords highlighted.
+type Snippet struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Revision is an immutable snapshot of the title and body of an article, recorded on every create and update.
+// RequestID is the id of the request which produced the revision.
+type Revision struct {
+	ArticleID string `json:"articleId"`
+	Revision  int    `json:"revision"`
+
+	Title string `json:"title"`
+	Body  string `json:"body"`
+
+	RequestID string    `json:"requestId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Revisions is used to present the history of an article, oldest revision first, in a JSON response.
+type Revisions struct {
+	Revisions []Revision `json:"revisions"`
+}
+
+// Diff presents the changes to the title and body of an article between two revisions in unified diff format.
+// A field is empty when it did not change.
+type Diff struct {
+	ArticleID string `json:"articleId"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Tag is a tag along with the number of live, published articles carrying it
+type Tag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Tags is used to present tags, most used first, in a JSON response.
+type Tags struct {
+	Tags []Tag `json:"tags"`
 }
@@ -0,0 +1,106 @@
+package articles
+
+import (
+	"bytes"
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// DefaultRenderCacheSize is the number of rendered bodies kept by the Renderer of the Service
+const DefaultRenderCacheSize = 1000
+
+// Renderer turns Markdown bodies into HTML which is safe to embed in a page. Raw HTML in the Markdown is
+// passed through the renderer and then sanitized, which drops scripts, event handler attributes and links
+// other than http(s) and mailto. Rendered bodies are cached per article and UpdatedAt, so only the first
+// read after a change pays for rendering; the least recently used bodies are evicted once the cache is full.
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+
+	mu    sync.Mutex
+	size  int
+	items map[renderKey]*list.Element
+	order *list.List
+}
+
+// renderKey identifies a body, which can only change along with the UpdatedAt of its article
+type renderKey struct {
+	id        string
+	updatedAt time.Time
+}
+
+type renderedBody struct {
+	key  renderKey
+	html string
+}
+
+// NewRenderer creates a Renderer which caches up to size rendered bodies
+func NewRenderer(size int) *Renderer {
+	if size <= 0 {
+		size = DefaultRenderCacheSize
+	}
+
+	policy := bluemonday.UGCPolicy()
+	policy.AllowURLSchemes("http", "https", "mailto")
+	policy.RequireParseableURLs(true)
+
+	return &Renderer{
+		markdown: goldmark.New(goldmark.WithExtensions(extension.GFM), goldmark.WithRendererOptions(html.WithUnsafe())),
+		policy:   policy,
+		size:     size,
+		items:    make(map[renderKey]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Render returns the sanitized HTML of the body of the article
+func (r *Renderer) Render(ar Article) (string, error) {
+	key := renderKey{id: ar.ID, updatedAt: ar.UpdatedAt.UTC()}
+	if body, ok := r.cached(key); ok {
+		return body, nil
+	}
+
+	var buf bytes.Buffer
+	if err := r.markdown.Convert([]byte(ar.Body), &buf); err != nil {
+		return "", err
+	}
+	body := string(r.policy.SanitizeBytes(buf.Bytes()))
+
+	r.store(key, body)
+	return body, nil
+}
+
+func (r *Renderer) cached(key renderKey) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.items[key]
+	if !ok {
+		return "", false
+	}
+	r.order.MoveToFront(e)
+	return e.Value.(*renderedBody).html, true
+}
+
+func (r *Renderer) store(key renderKey, body string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.items[key]; ok {
+		r.order.MoveToFront(e)
+		return
+	}
+	r.items[key] = r.order.PushFront(&renderedBody{key: key, html: body})
+
+	for r.order.Len() > r.size {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.items, oldest.Value.(*renderedBody).key)
+	}
+}
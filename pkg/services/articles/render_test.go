@@ -0,0 +1,67 @@
+package articles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRendererRender(t *testing.T) {
+	tests := map[string]struct {
+		body   string
+		expect string
+	}{
+		"Markdown": {
+			body:   "# Title\n\nSome *body* with a [link](https://example.com).",
+			expect: "<h1>Title</h1>\n<p>Some <em>body</em> with a <a href=\"https://example.com\" rel=\"nofollow\">link</a>.</p>\n",
+		},
+		"Script": {
+			body:   "before\n\n<script>alert(1)</script>\n\nafter",
+			expect: "<p>before</p>\n\n<p>after</p>\n",
+		},
+		"Event handler": {
+			body:   `<img src="https://example.com/a.png" onerror="alert(1)">`,
+			expect: `<img src="https://example.com/a.png">`,
+		},
+		"Javascript link": {
+			body:   "[click](javascript:alert(1))",
+			expect: "<p>click</p>\n",
+		},
+		"Javascript link in HTML": {
+			body:   `<a href="JaVaScRiPt:alert(1)">click</a>`,
+			expect: "<p>click</p>\n",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			r := NewRenderer(10)
+			html, err := r.Render(Article{ID: "id", Body: test.body})
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, html)
+		})
+	}
+}
+
+func TestRendererCache(t *testing.T) {
+	r := NewRenderer(1)
+	now := time.Now()
+
+	html, _ := r.Render(Article{ID: "a", Body: "first", UpdatedAt: now})
+	assert.Equal(t, "<p>first</p>\n", html)
+
+	// the body cannot change without UpdatedAt changing as well, so the cached rendering is used
+	html, _ = r.Render(Article{ID: "a", Body: "second", UpdatedAt: now})
+	assert.Equal(t, "<p>first</p>\n", html)
+
+	html, _ = r.Render(Article{ID: "a", Body: "second", UpdatedAt: now.Add(time.Second)})
+	assert.Equal(t, "<p>second</p>\n", html)
+
+	// rendering another article evicts the least recently used body
+	r.Render(Article{ID: "b", Body: "other", UpdatedAt: now})
+	html, _ = r.Render(Article{ID: "a", Body: "third", UpdatedAt: now.Add(time.Second)})
+	assert.Equal(t, "<p>third</p>\n", html)
+}
@@ -0,0 +1,73 @@
+package articles
+
+import (
+	"context"
+	"time"
+
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+// DefaultScheduleInterval is how often the Scheduler looks for due articles unless told otherwise
+const DefaultScheduleInterval = time.Minute
+
+// scheduleBatchSize is the number of articles moved per statement, which keeps the row locks short lived
+const scheduleBatchSize = 100
+
+// Scheduler periodically publishes the articles in review whose PublishAt has passed and archives the published
+// articles whose ExpireAt has passed. Any number of schedulers can run against the same database.
+type Scheduler struct {
+	repo     Repo
+	interval time.Duration
+}
+
+// NewScheduler creates a Scheduler which looks for due articles every interval
+func NewScheduler(repo Repo, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultScheduleInterval
+	}
+	return &Scheduler{repo: repo, interval: interval}
+}
+
+// Run looks for due articles right away and then on every interval, until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	log.Info(ctx, "scheduler started, checking every %s", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if published, expired, err := s.Tick(ctx); err == nil && published+expired > 0 {
+			log.Info(ctx, "scheduler published %d and expired %d articles", published, expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info(ctx, "scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick publishes and then expires the due articles, batch by batch until none are left or the context is
+// cancelled. An article whose schedule has passed entirely is published and archived within the same tick.
+func (s *Scheduler) Tick(ctx context.Context) (published, expired int, err error) {
+	published, err = s.drain(ctx, s.repo.PublishDue)
+	if err != nil {
+		return published, 0, err
+	}
+	expired, err = s.drain(ctx, s.repo.ExpireDue)
+	return published, expired, err
+}
+
+// drain runs move until it moves less than a full batch
+func (s *Scheduler) drain(ctx context.Context, move func(ctx context.Context, limit int) (int, error)) (int, error) {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := move(ctx, scheduleBatchSize)
+		total += n
+		if err != nil || n < scheduleBatchSize {
+			return total, err
+		}
+	}
+	return total, ctx.Err()
+}
@@ -0,0 +1,58 @@
+package articles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerTick(t *testing.T) {
+	tests := map[string]struct {
+		repo      *repoMock
+		published int
+		expired   int
+		err       error
+	}{
+		"Nothing due": {
+			repo: &repoMock{},
+		},
+		"Single batches": {
+			repo:      &repoMock{PublishDueResults: []int{3}, ExpireDueResults: []int{1}},
+			published: 3,
+			expired:   1,
+		},
+		"Drains full batches": {
+			repo:      &repoMock{PublishDueResults: []int{scheduleBatchSize, scheduleBatchSize, 7}, ExpireDueResults: []int{scheduleBatchSize}},
+			published: 2*scheduleBatchSize + 7,
+			expired:   scheduleBatchSize,
+		},
+		"Repo failure": {
+			repo: &repoMock{DueError: ErrArticleUpdate},
+			err:  ErrArticleUpdate,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			s := NewScheduler(test.repo, 0)
+			published, expired, err := s.Tick(context.Background())
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.published, published)
+			assert.Equal(t, test.expired, expired)
+		})
+	}
+}
+
+func TestSchedulerRunStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewScheduler(&repoMock{}, 0).Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,49 @@
+package articles
+
+import (
+	"strings"
+)
+
+// MaxSlugLength is the maximum number of characters of the part of a slug derived from the title
+const MaxSlugLength = 80
+
+// defaultSlug stands in for titles which do not contain any letters or digits
+const defaultSlug = "article"
+
+// Slugify derives the base of a slug from a title: ASCII letters are lower cased, ASCII digits are kept and
+// every run of other characters, letters outside of ASCII included, becomes a single "-". Sticking to ASCII
+// keeps the rule independent of the locale, so the slugs migration 14 backfilled in SQL follow it as well.
+// Slugs which are already taken get a "-2", "-3", ... suffix when the article is stored.
+func Slugify(title string) string {
+	var b strings.Builder
+	n := 0
+	dash := false
+	for _, r := range title {
+		if n == MaxSlugLength {
+			break
+		}
+		if 'A' <= r && r <= 'Z' {
+			r += 'a' - 'A'
+		}
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			dash = b.Len() > 0
+			continue
+		}
+		if dash {
+			b.WriteByte('-')
+			n++
+			dash = false
+			if n == MaxSlugLength {
+				break
+			}
+		}
+		b.WriteRune(r)
+		n++
+	}
+
+	slug := strings.TrimRight(b.String(), "-")
+	if slug == "" {
+		return defaultSlug
+	}
+	return slug
+}
@@ -0,0 +1,31 @@
+package articles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]struct {
+		title string
+		slug  string
+	}{
+		"Words":          {title: "Hello World", slug: "hello-world"},
+		"Punctuation":    {title: "  Go 1.15: what's new?!  ", slug: "go-1-15-what-s-new"},
+		"Accents":        {title: "Crème Brûlée", slug: "cr-me-br-l-e"},
+		"Unicode":        {title: "Grüße aus Köln", slug: "gr-e-aus-k-ln"},
+		"Only Unicode":   {title: "Ελληνικά", slug: "article"},
+		"No letters":     {title: "?!?", slug: "article"},
+		"Empty":          {title: "", slug: "article"},
+		"Truncated":      {title: strings.Repeat("a", 100), slug: strings.Repeat("a", MaxSlugLength)},
+		"Truncated dash": {title: strings.Repeat("a", MaxSlugLength-1) + " b", slug: strings.Repeat("a", MaxSlugLength-1)},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, test.slug, Slugify(test.title))
+		})
+	}
+}
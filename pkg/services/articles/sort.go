@@ -0,0 +1,55 @@
+package articles
+
+import (
+	"strings"
+	"time"
+)
+
+// Fields articles can be sorted by, named as they appear in the JSON representation
+const (
+	SortTitle     = "title"
+	SortCreatedAt = "createdAt"
+	SortUpdatedAt = "updatedAt"
+)
+
+// SortableFields is the whitelist of fields articles can be sorted by
+var SortableFields = map[string]bool{
+	SortTitle:     true,
+	SortCreatedAt: true,
+	SortUpdatedAt: true,
+}
+
+// DefaultSort orders articles from oldest to newest
+var DefaultSort = []SortField{{Field: SortCreatedAt}}
+
+// SortField is one key of the ordering of a listing
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// FormatSort is the inverse of parsing a sort parameter, e.g. "-updatedAt,title"
+func FormatSort(sort []SortField) string {
+	keys := make([]string, len(sort))
+	for i, s := range sort {
+		keys[i] = s.Field
+		if s.Desc {
+			keys[i] = "-" + s.Field
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// sortValue is the value of a sortable field on an article, as stored in a cursor
+func sortValue(ar Article, field string) string {
+	switch field {
+	case SortTitle:
+		return ar.Title
+	case SortCreatedAt:
+		return ar.CreatedAt.Format(time.RFC3339Nano)
+	case SortUpdatedAt:
+		return ar.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		return ""
+	}
+}
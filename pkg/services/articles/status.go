@@ -0,0 +1,38 @@
+package articles
+
+// Status is the stage of the editorial workflow an article is in
+type Status string
+
+// Statuses of the workflow. Articles start out as drafts and only published articles are listed by default.
+const (
+	StatusDraft     Status = "draft"
+	StatusInReview  Status = "in_review"
+	StatusPublished Status = "published"
+	StatusArchived  Status = "archived"
+)
+
+// Statuses is the whitelist of statuses articles can be listed by
+var Statuses = map[Status]bool{
+	StatusDraft:     true,
+	StatusInReview:  true,
+	StatusPublished: true,
+	StatusArchived:  true,
+}
+
+// Transition moves an article from one status to the next
+type Transition struct {
+	Name string
+	From Status
+	To   Status
+}
+
+// Transitions of the workflow, keyed by name:
+//
+//	draft --submit--> in_review --publish--> published --archive--> archived
+//	      <--reject--
+var Transitions = map[string]Transition{
+	"submit":  {Name: "submit", From: StatusDraft, To: StatusInReview},
+	"reject":  {Name: "reject", From: StatusInReview, To: StatusDraft},
+	"publish": {Name: "publish", From: StatusInReview, To: StatusPublished},
+	"archive": {Name: "archive", From: StatusPublished, To: StatusArchived},
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+// Every operation of a batch runs in the same transaction, each behind a savepoint. A failed
+// operation is rolled back to its savepoint, which undoes just that operation and leaves the
+// transaction usable for the ones after it.
+const (
+	savepointBatchItem = `SAVEPOINT batch_item`
+	rollbackBatchItem  = `ROLLBACK TO SAVEPOINT batch_item`
+	releaseBatchItem   = `RELEASE SAVEPOINT batch_item`
+)
+
+// errBatchFailed rolls back an atomic batch in which an operation failed
+var errBatchFailed = errors.New("batch operation failed")
+
+// Batch applies the operations in order within a single transaction. Failed operations are undone on their own,
+// so all operations are attempted and reported on. When atomic is set the transaction is then rolled back if any of
+// them failed, leaving the results of the other operations without an article. Only the ID of the articles is set.
+func (r *articleRepo) Batch(ctx context.Context, ops []articles.BatchOperation, atomic bool) ([]articles.BatchResult, error) {
+	results := make([]articles.BatchResult, len(ops))
+	err := r.inTx(ctx, articles.ErrArticleBatch, func(tx *sql.Tx) error {
+		failed := false
+		for i, op := range ops {
+			if _, err := tx.Exec(savepointBatchItem); err != nil {
+				log.Error(ctx, "unable to start batch operation %d: %s", i, err.Error())
+				return articles.ErrArticleBatch
+			}
+
+			id, err := applyBatchOperation(ctx, tx, op)
+			if err != nil {
+				failed = true
+				results[i].Err = err
+				if _, err := tx.Exec(rollbackBatchItem); err != nil {
+					log.Error(ctx, "unable to undo batch operation %d: %s", i, err.Error())
+					return articles.ErrArticleBatch
+				}
+				continue
+			}
+
+			if _, err := tx.Exec(releaseBatchItem); err != nil {
+				log.Error(ctx, "unable to finish batch operation %d: %s", i, err.Error())
+				return articles.ErrArticleBatch
+			}
+			results[i].Article.ID = id
+		}
+
+		if failed && atomic {
+			return errBatchFailed
+		}
+		return nil
+	})
+
+	if err == errBatchFailed {
+		for i := range results {
+			results[i].Article = articles.Article{}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// applyBatchOperation creates or updates the article of the operation and returns its id
+func applyBatchOperation(ctx context.Context, tx *sql.Tx, op articles.BatchOperation) (string, error) {
+	if op.Op == articles.BatchCreate {
+		return createArticle(ctx, tx, op.Article)
+	}
+	return op.ID, updateArticleTx(ctx, tx, op.Article, op.ID, op.Version)
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestArticleRepoBatch(t *testing.T) {
+	createdID := uuid.New().String()
+	missingID := uuid.New().String()
+	ops := []articles.BatchOperation{
+		{Op: articles.BatchCreate, Article: articles.ArticleCreateUpdate{Title: "title", Body: "body"}},
+		{Op: articles.BatchUpdate, ID: missingID, Version: 2, Article: articles.ArticleCreateUpdate{Title: "title", Body: "body"}},
+	}
+
+	tests := map[string]struct {
+		atomic         bool
+		savepointError error
+		expect         []articles.BatchResult
+		err            error
+	}{
+		"Best effort": {
+			expect: []articles.BatchResult{{Article: articles.Article{ID: createdID}}, {Err: articles.ErrArticleNotFound}},
+		},
+		"Atomic": {
+			atomic: true,
+			expect: []articles.BatchResult{{}, {Err: articles.ErrArticleNotFound}},
+		},
+		"Savepoint error": {
+			savepointError: errors.New("some-db-error"),
+			err:            articles.ErrArticleBatch,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(savepointBatchItem)).WillReturnError(test.savepointError).WillReturnResult(sqlmock.NewResult(0, 0))
+			if test.savepointError == nil {
+				expectPickSlug(mock, "title")
+				mock.ExpectQuery(regexp.QuoteMeta(insertArticle)).WithArgs("title", "body", nil, nil, nil, "title").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(createdID))
+				mock.ExpectExec(regexp.QuoteMeta(insertSlug)).WithArgs("title", createdID).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectSetTags(mock, createdID, nil)
+				mock.ExpectExec(regexp.QuoteMeta(insertRevision)).WithArgs(createdID, "").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(releaseBatchItem)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectExec(regexp.QuoteMeta(savepointBatchItem)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(updateArticle)).WithArgs("title", "body", nil, nil, nil, missingID, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta(liveArticleExists)).WithArgs(missingID).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectExec(regexp.QuoteMeta(rollbackBatchItem)).WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+			if test.atomic || test.savepointError != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			repo := New(db)
+			results, err := repo.Batch(context.Background(), ops, test.atomic)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expect, results)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
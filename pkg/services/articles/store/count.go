@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+// exactCountLimit is the number of articles, going by pg_class.reltuples, above which articles.TotalAuto
+// estimates the total, since counting exactly means reading every article of the listing
+const exactCountLimit = 100000
+
+const (
+	countArticles   = `SELECT COUNT(*) FROM %s`
+	explainArticles = `EXPLAIN (FORMAT JSON) SELECT 1 FROM %s`
+
+	// estimateArticleRows is the number of rows of the articles table as of the last VACUUM or ANALYZE.
+	// It is -1 for a table which was never analyzed.
+	estimateArticleRows = `SELECT reltuples::bigint FROM pg_class WHERE oid = 'articles'::regclass`
+)
+
+// rowQuerier runs queries returning a single row, like *sql.DB and *sql.Tx do
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Count counts the articles of the listing, ignoring its paging, the way p.Total asks for (see count)
+func (r *articleRepo) Count(ctx context.Context, p articles.ListParams) (total int64, estimated bool, err error) {
+	b, from := listing(p)
+	return count(ctx, r.DB, p.Total, from+b.conditions(), b.args)
+}
+
+// count counts the rows of the table expression from, which includes its WHERE clause, the way mode asks for.
+// An estimate is the number of rows the query planner expects, which it derives from pg_class.reltuples and the
+// statistics of the columns the rows are narrowed down by; estimated is set when the total is one.
+func count(ctx context.Context, db rowQuerier, mode articles.TotalCount, from string, args []interface{}) (total int64, estimated bool, err error) {
+	estimate := mode == articles.TotalEstimate
+	if mode == articles.TotalAuto {
+		var rows int64
+		if err := db.QueryRow(estimateArticleRows).Scan(&rows); err != nil {
+			log.Warn(ctx, "unable to estimate the number of articles: %s", err.Error())
+			return 0, false, articles.ErrArticleQuery
+		}
+		estimate = rows > exactCountLimit
+	}
+
+	if !estimate {
+		if err := db.QueryRow(fmt.Sprintf(countArticles, from), args...).Scan(&total); err != nil {
+			log.Warn(ctx, "unable to count articles: %s", err.Error())
+			return 0, false, articles.ErrArticleQuery
+		}
+		return total, false, nil
+	}
+
+	var plan []byte
+	if err := db.QueryRow(fmt.Sprintf(explainArticles, from), args...).Scan(&plan); err != nil {
+		log.Warn(ctx, "unable to estimate the number of articles: %s", err.Error())
+		return 0, false, articles.ErrArticleQuery
+	}
+	total, err = planRows(plan)
+	if err != nil {
+		log.Warn(ctx, "unable to read the query plan: %s", err.Error())
+		return 0, false, articles.ErrArticleQuery
+	}
+	return total, true, nil
+}
+
+// planRows reads the number of rows the query planner expects from the JSON output of EXPLAIN
+func planRows(plan []byte) (int64, error) {
+	var explained []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		}
+	}
+	if err := json.Unmarshal(plan, &explained); err != nil {
+		return 0, err
+	}
+	if len(explained) == 0 {
+		return 0, fmt.Errorf("query plan is empty")
+	}
+	return int64(explained[0].Plan.Rows), nil
+}
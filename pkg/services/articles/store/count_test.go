@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestArticleRepoCount(t *testing.T) {
+	const (
+		count   = `SELECT COUNT(*) FROM articles WHERE ($1 OR disabled_at IS NULL) AND status = ANY($2)`
+		explain = `EXPLAIN (FORMAT JSON) SELECT 1 FROM articles WHERE ($1 OR disabled_at IS NULL) AND status = ANY($2)`
+		plan    = `[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "articles", "Plan Rows": 180321, "Plan Width": 0}}]`
+	)
+	statuses := []articles.Status{articles.StatusPublished}
+
+	tests := map[string]struct {
+		total     articles.TotalCount
+		tableRows int64
+		expect    func(mock sqlmock.Sqlmock)
+		count     int64
+		estimated bool
+		err       error
+	}{
+		"Exact": {
+			total: articles.TotalExact,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(count)).WithArgs(false, pq.Array([]string{"published"})).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+			},
+			count: 42,
+		},
+		"Estimate": {
+			total: articles.TotalEstimate,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(explain)).WithArgs(false, pq.Array([]string{"published"})).
+					WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow(plan))
+			},
+			count:     180321,
+			estimated: true,
+		},
+		"Auto on a small table": {
+			total: articles.TotalAuto,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(estimateArticleRows)).WillReturnRows(sqlmock.NewRows([]string{"reltuples"}).AddRow(5000))
+				mock.ExpectQuery(regexp.QuoteMeta(count)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+			},
+			count: 42,
+		},
+		"Auto on a large table": {
+			total: articles.TotalAuto,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(estimateArticleRows)).WillReturnRows(sqlmock.NewRows([]string{"reltuples"}).AddRow(exactCountLimit + 1))
+				mock.ExpectQuery(regexp.QuoteMeta(explain)).WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow(plan))
+			},
+			count:     180321,
+			estimated: true,
+		},
+		"Count error": {
+			total: articles.TotalExact,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(count)).WillReturnError(errors.New("some-db-error"))
+			},
+			err: articles.ErrArticleQuery,
+		},
+		"Unreadable plan": {
+			total: articles.TotalEstimate,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(explain)).WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow(`[]`))
+			},
+			err: articles.ErrArticleQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			test.expect(mock)
+
+			repo := New(db)
+			total, estimated, err := repo.Count(context.Background(), articles.ListParams{
+				Limit:    25,
+				Offset:   50,
+				Cursor:   &articles.Cursor{Sort: "createdAt", Values: []string{"2021-04-01T12:00:00Z"}, ID: "id"},
+				Statuses: statuses,
+				Total:    test.total,
+			})
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.count, total)
+			assert.Equal(t, test.estimated, estimated)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
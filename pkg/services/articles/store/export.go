@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+// exportFetchSize is the number of rows fetched from the export cursor at a time
+const exportFetchSize = 500
+
+// The export reads from a server-side cursor, so only a single fetch of rows is held in memory at any time.
+// Unpublished articles are left out of the cursor unless they are asked for.
+var (
+	declareExportCursor = `DECLARE export_articles NO SCROLL CURSOR FOR SELECT ` + articleColumns + ` FROM articles WHERE disabled_at IS NULL%s ORDER BY created_at, id`
+	exportPublished     = ` AND status = 'published'`
+	fetchExportCursor   = fmt.Sprintf(`FETCH FORWARD %d FROM export_articles`, exportFetchSize)
+)
+
+// Export hands every live article, oldest first, to fn as it is read; unless includeUnpublished is set only the
+// published ones. All articles are read from the same snapshot. The export stops with the error of fn when it
+// fails, and with the error of the context when it is done.
+func (r *articleRepo) Export(ctx context.Context, includeUnpublished bool, fn func(articles.Article) error) error {
+	published := exportPublished
+	if includeUnpublished {
+		published = ""
+	}
+
+	return r.inTx(ctx, articles.ErrArticleQuery, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(declareExportCursor, published)); err != nil {
+			log.Warn(ctx, "unable to declare export cursor: %s", err.Error())
+			return articles.ErrArticleQuery
+		}
+
+		for {
+			n, err := fetchExport(ctx, tx, fn)
+			if err != nil {
+				return err
+			}
+			if n < exportFetchSize {
+				return nil
+			}
+		}
+	})
+}
+
+// fetchExport hands the next rows of the export cursor to fn and returns how many there were
+func fetchExport(ctx context.Context, tx *sql.Tx, fn func(articles.Article) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fetchExportCursor)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		log.Warn(ctx, "unable to fetch from export cursor: %s", err.Error())
+		return 0, articles.ErrArticleQuery
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		var ar articles.Article
+		if err := rows.Scan(articleDest(&ar)...); err != nil {
+			log.Error(ctx, "unable to scan db rows: %s", err.Error())
+			return n, articles.ErrArticleQuery
+		}
+		n++
+
+		if err := fn(ar); err != nil {
+			return n, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		if ctx.Err() != nil {
+			return n, ctx.Err()
+		}
+		log.Warn(ctx, "unable to fetch from export cursor: %s", err.Error())
+		return n, articles.ErrArticleQuery
+	}
+	return n, nil
+}
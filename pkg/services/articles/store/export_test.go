@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestArticleRepoExport(t *testing.T) {
+	columns := []string{"id", "slug", "title", "body", "created_at", "updated_at", "disabled_at", "version", "author_id", "status", "publish_at", "expire_at", "tags"}
+	now := time.Now()
+
+	tests := map[string]struct {
+		includeUnpublished bool
+		expectWhere        string
+		fnErr              error
+		fetchErr           error
+		expectRead         int
+		err                error
+	}{
+		"Happy path": {
+			expectWhere: `disabled_at IS NULL AND status = 'published'`,
+			expectRead:  exportFetchSize + 1,
+		},
+		"Unpublished included": {
+			includeUnpublished: true,
+			expectWhere:        `disabled_at IS NULL`,
+			expectRead:         exportFetchSize + 1,
+		},
+		"Writer error": {
+			expectWhere: `disabled_at IS NULL AND status = 'published'`,
+			fnErr:       errors.New("broken pipe"),
+			expectRead:  1,
+			err:         errors.New("broken pipe"),
+		},
+		"Fetch error": {
+			expectWhere: `disabled_at IS NULL AND status = 'published'`,
+			fetchErr:    errors.New("some-db-error"),
+			expectRead:  exportFetchSize,
+			err:         articles.ErrArticleQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(`DECLARE export_articles NO SCROLL CURSOR FOR SELECT ` + articleColumns +
+				` FROM articles WHERE ` + test.expectWhere + ` ORDER BY created_at, id`)).WillReturnResult(sqlmock.NewResult(0, 0))
+			// the first fetch returns as many rows as were asked for, so another fetch follows it
+			fullFetch := sqlmock.NewRows(columns)
+			for i := 0; i < exportFetchSize; i++ {
+				fullFetch.AddRow("id", "slug", "title", "body", now, now, nil, 1, nil, "published", nil, nil, "{}")
+			}
+			mock.ExpectQuery(regexp.QuoteMeta(fetchExportCursor)).WillReturnRows(fullFetch)
+			if test.fetchErr != nil {
+				mock.ExpectQuery(regexp.QuoteMeta(fetchExportCursor)).WillReturnError(test.fetchErr)
+			} else if test.fnErr == nil {
+				mock.ExpectQuery(regexp.QuoteMeta(fetchExportCursor)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("id", "slug", "title", "body", now, now, nil, 1, nil, "published", nil, nil, "{go}"))
+			}
+			if test.err == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			read := 0
+			repo := New(db)
+			err := repo.Export(context.Background(), test.includeUnpublished, func(ar articles.Article) error {
+				read++
+				return test.fnErr
+			})
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expectRead, read)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
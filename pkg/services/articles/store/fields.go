@@ -0,0 +1,82 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+// tagsColumn aggregates the tags of each article into an array of their names
+const tagsColumn = `ARRAY(SELECT name FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id ORDER BY name) AS tags`
+
+// fieldColumn is the column a field of an article is read from and where it is scanned to
+type fieldColumn struct {
+	field  string
+	column string
+	dest   func(ar *articles.Article) interface{}
+}
+
+// fieldColumns are the articleColumns along with the fields they fill in, in the same order
+var fieldColumns = []fieldColumn{
+	{"id", "id", func(ar *articles.Article) interface{} { return &ar.ID }},
+	{"slug", "slug", func(ar *articles.Article) interface{} { return &ar.Slug }},
+	{"title", "title", func(ar *articles.Article) interface{} { return &ar.Title }},
+	{"body", "body", func(ar *articles.Article) interface{} { return &ar.Body }},
+	{"createdAt", "created_at", func(ar *articles.Article) interface{} { return &ar.CreatedAt }},
+	{"updatedAt", "updated_at", func(ar *articles.Article) interface{} { return &ar.UpdatedAt }},
+	{"disabledAt", "disabled_at", func(ar *articles.Article) interface{} { return &ar.DisabledAt }},
+	{"version", "version", func(ar *articles.Article) interface{} { return &ar.Version }},
+	{"authorId", "author_id", func(ar *articles.Article) interface{} { return &ar.AuthorID }},
+	{"status", "status", func(ar *articles.Article) interface{} { return &ar.Status }},
+	{"publishAt", "publish_at", func(ar *articles.Article) interface{} { return &ar.PublishAt }},
+	{"expireAt", "expire_at", func(ar *articles.Article) interface{} { return &ar.ExpireAt }},
+	{"tags", tagsColumn, func(ar *articles.Article) interface{} { return pq.Array(&ar.Tags) }},
+}
+
+// selectedFields are read whatever fields are asked for: the id and version identify the article and,
+// along with updatedAt, make up its validators and the key of its rendered body, its status decides who
+// gets to read it
+var selectedFields = []string{"id", "version", "updatedAt", "status"}
+
+// derivedFields are not stored but filled in from the field they are derived from
+var derivedFields = map[string]string{
+	"bodyHtml": "body",
+	"author":   "authorId",
+}
+
+// selection renders the columns to select for the fields and where they are scanned to. No fields means all of
+// them; otherwise the selectedFields, the fields the given ones derive from and the extra ones are read as well.
+func selection(fields []string, extra ...string) (string, func(ar *articles.Article) []interface{}) {
+	if len(fields) == 0 {
+		return articleColumns, articleDest
+	}
+
+	want := make(map[string]bool)
+	for _, fs := range [][]string{fields, selectedFields, extra} {
+		for _, f := range fs {
+			if from, ok := derivedFields[f]; ok {
+				f = from
+			}
+			want[f] = true
+		}
+	}
+
+	var columns []string
+	var selected []fieldColumn
+	for _, fc := range fieldColumns {
+		if want[fc.field] {
+			columns = append(columns, fc.column)
+			selected = append(selected, fc)
+		}
+	}
+
+	return strings.Join(columns, ", "), func(ar *articles.Article) []interface{} {
+		dest := make([]interface{}, len(selected))
+		for i, fc := range selected {
+			dest[i] = fc.dest(ar)
+		}
+		return dest
+	}
+}
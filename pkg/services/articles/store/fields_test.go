@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestSelection(t *testing.T) {
+	tests := map[string]struct {
+		fields  []string
+		extra   []string
+		columns string
+		dests   int
+	}{
+		"All fields": {
+			columns: articleColumns,
+			dests:   13,
+		},
+		"Some fields": {
+			fields:  []string{"title", "id"},
+			columns: "id, title, updated_at, version, status",
+			dests:   5,
+		},
+		"Derived fields": {
+			fields:  []string{"bodyHtml", "author"},
+			columns: "id, body, updated_at, version, author_id, status",
+			dests:   6,
+		},
+		"Tags": {
+			fields:  []string{"tags"},
+			columns: "id, updated_at, version, status, " + tagsColumn,
+			dests:   5,
+		},
+		"Sort fields": {
+			fields:  []string{"slug"},
+			extra:   []string{articles.SortCreatedAt, articles.SortTitle},
+			columns: "id, slug, title, created_at, updated_at, version, status",
+			dests:   7,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			columns, dest := selection(test.fields, test.extra...)
+
+			assert.Equal(t, test.columns, columns)
+			assert.Len(t, dest(&articles.Article{}), test.dests)
+		})
+	}
+}
+
+func TestSelectionCoversArticleColumns(t *testing.T) {
+	fields := make([]string, 0, len(fieldColumns))
+	for _, fc := range fieldColumns {
+		fields = append(fields, fc.field)
+		assert.True(t, articles.Fields[fc.field], fc.field)
+	}
+
+	columns, dest := selection(fields)
+
+	assert.Equal(t, articleColumns, columns)
+	assert.Len(t, dest(&articles.Article{}), len(articleDest(&articles.Article{})))
+}
+
+func TestArticleRepoGetFields(t *testing.T) {
+	id := uuid.New().String()
+	now := time.Now()
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(selectArticle, "id, title, updated_at, version, status"))).
+		WithArgs(id, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "updated_at", "version", "status"}).AddRow(id, "title", now, 3, "draft"))
+
+	repo := New(db)
+	response, err := repo.Get(context.Background(), id, false, []string{"id", "title"})
+
+	require.NoError(t, err)
+	assert.Equal(t, articles.Article{ID: id, Title: "title", UpdatedAt: now, Version: 3, Status: articles.StatusDraft}, response)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleRepoGetAllFields(t *testing.T) {
+	id := uuid.New().String()
+	now := time.Now()
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, updated_at, version, status FROM articles WHERE ($1 OR disabled_at IS NULL) ORDER BY title, id LIMIT $2 OFFSET $3`)).
+		WithArgs(false, 25, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "updated_at", "version", "status"}).AddRow(id, "title", now, 3, "published"))
+
+	repo := New(db)
+	response, err := repo.GetAll(context.Background(), articles.ListParams{
+		Limit:  25,
+		Sort:   []articles.SortField{{Field: articles.SortTitle}},
+		Fields: []string{"id"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []articles.Article{{ID: id, Title: "title", UpdatedAt: now, Version: 3, Status: articles.StatusPublished}}, response)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleRepoGetAllBeforeCursor(t *testing.T) {
+	id1, id2 := uuid.New().String(), uuid.New().String()
+	now := time.Now()
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, updated_at, version, status FROM articles WHERE ($1 OR disabled_at IS NULL) `+
+		`AND ((title < $2) OR (title = $2 AND id < $3)) ORDER BY title DESC, id DESC LIMIT $4 OFFSET $5`)).
+		WithArgs(false, "c", id2, 2, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "updated_at", "version", "status"}).
+			AddRow(id2, "b", now, 1, "published").
+			AddRow(id1, "a", now, 1, "published"))
+
+	repo := New(db)
+	response, err := repo.GetAll(context.Background(), articles.ListParams{
+		Limit:  2,
+		Sort:   []articles.SortField{{Field: articles.SortTitle}},
+		Cursor: &articles.Cursor{Sort: "title", Values: []string{"c"}, ID: id2, Before: true},
+		Fields: []string{"id"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, response, 2)
+	assert.Equal(t, []string{id1, id2}, []string{response[0].ID, response[1].ID})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+const (
+	// articlesAsOf stands in for the articles table as it was at the time given by the placeholder.
+	// Every change to an article is recorded in article_history by a trigger along with the period
+	// during which that state was current.
+	articlesAsOf = `(SELECT (jsonb_populate_record(NULL::articles, data)).* FROM article_history ` +
+		`WHERE valid_from <= %[1]s AND (valid_to IS NULL OR valid_to > %[1]s)) AS articles`
+
+	selectArticleAsOf = `SELECT ` + articleColumns + ` FROM (SELECT (jsonb_populate_record(NULL::articles, data)).* FROM article_history ` +
+		`WHERE article_id = $1 AND valid_from <= $2 AND (valid_to IS NULL OR valid_to > $2)) AS articles WHERE ($3 OR disabled_at IS NULL)`
+)
+
+// GetAsOf retrieves the article with the given id as it was at the given time. Articles which did not exist yet,
+// had been purged or (unless includeDisabled is set) were disabled at that time are not found. The history
+// only covers the articles table, so the tags are those the article carries now.
+func (r *articleRepo) GetAsOf(ctx context.Context, id string, at time.Time, includeDisabled bool) (articles.Article, error) {
+	var ar articles.Article
+
+	err := r.DB.QueryRow(selectArticleAsOf, id, at, includeDisabled).
+		Scan(articleDest(&ar)...)
+	if err != nil {
+		log.Info(ctx, "select article as of %s error: %s", at.Format(time.RFC3339), err.Error())
+		return ar, articles.ErrArticleNotFound
+	}
+
+	return ar, nil
+}
+
+// source renders the table articles are listed from, which is either the current
+// state of the articles or their state at the time given by asOf.
+func (b *builder) source(asOf *time.Time) string {
+	if asOf == nil {
+		return "articles"
+	}
+	return fmt.Sprintf(articlesAsOf, b.arg(*asOf))
+}
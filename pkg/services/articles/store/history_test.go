@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestArticleRepoGetAsOf(t *testing.T) {
+	columns := []string{"id", "slug", "title", "body", "created_at", "updated_at", "disabled_at", "version", "author_id", "status", "publish_at", "expire_at", "tags"}
+	id := uuid.New().String()
+	now := time.Now()
+	at := now.Add(-time.Hour)
+
+	tests := map[string]struct {
+		expectQueryRows  *sqlmock.Rows
+		expectQueryError error
+		expect           articles.Article
+		err              error
+	}{
+		"Happy path": {
+			expectQueryRows: sqlmock.NewRows(columns).AddRow(id, "old-title", "old title", "old body", now, now, nil, 2, nil, "published", nil, nil, "{go}"),
+			expect:          articles.Article{ID: id, Slug: "old-title", Title: "old title", Body: "old body", CreatedAt: now, UpdatedAt: now, Version: 2, Status: articles.StatusPublished, Tags: []string{"go"}},
+		},
+		"Not existing or disabled at the time": {
+			expectQueryRows: sqlmock.NewRows(columns),
+			err:             articles.ErrArticleNotFound,
+		},
+		"Query error": {
+			expectQueryError: errors.New("some-db-error"),
+			err:              articles.ErrArticleNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			q := mock.ExpectQuery(regexp.QuoteMeta(selectArticleAsOf)).WithArgs(id, at, false).WillReturnError(test.expectQueryError)
+			if test.expectQueryRows != nil {
+				q.WillReturnRows(test.expectQueryRows)
+			}
+
+			repo := New(db)
+			response, err := repo.GetAsOf(context.Background(), id, at, false)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expect, response)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+// sortColumns translates the sortable fields of an article into their columns. Only
+// these columns ever make it into an ORDER BY clause.
+var sortColumns = map[string]string{
+	articles.SortTitle:     "title",
+	articles.SortCreatedAt: "created_at",
+	articles.SortUpdatedAt: "updated_at",
+}
+
+// builder accumulates the conditions and arguments of a dynamically built query
+type builder struct {
+	where []string
+	args  []interface{}
+}
+
+// arg adds an argument to the query and returns its placeholder
+func (b *builder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// and adds a condition which all returned rows have to satisfy
+func (b *builder) and(cond string) {
+	b.where = append(b.where, cond)
+}
+
+// conditions renders the WHERE clause of the query
+func (b *builder) conditions() string {
+	if len(b.where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.where, " AND ")
+}
+
+// orderBy renders the ORDER BY clause for the sort, always using the id as the final tie breaker. A reversed
+// clause orders every key the other way around, which is how the page before a cursor is read.
+func orderBy(sort []articles.SortField, reverse bool) (string, error) {
+	keys := make([]string, 0, len(sort)+1)
+	for _, s := range sort {
+		col, ok := sortColumns[s.Field]
+		if !ok {
+			return "", fmt.Errorf("unknown sort field %q", s.Field)
+		}
+		if s.Desc != reverse {
+			col += " DESC"
+		}
+		keys = append(keys, col)
+	}
+	id := "id"
+	if reverse {
+		id += " DESC"
+	}
+	return " ORDER BY " + strings.Join(append(keys, id), ", "), nil
+}
+
+// afterCursor adds the keyset condition which skips every row up to and including the cursor. For the
+// sort keys k1..kn this is (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... OR (k1 = v1 AND ... AND kn = vn AND id > id),
+// where > becomes < for descending keys. For a cursor pointing Before, every row from the cursor on is
+// skipped instead, so all the comparisons are turned around.
+func (b *builder) afterCursor(sort []articles.SortField, c articles.Cursor) error {
+	if len(c.Values) != len(sort) {
+		return fmt.Errorf("cursor has %d values for %d sort fields", len(c.Values), len(sort))
+	}
+
+	var ors, eqs []string
+	for i, s := range sort {
+		col, ok := sortColumns[s.Field]
+		if !ok {
+			return fmt.Errorf("unknown sort field %q", s.Field)
+		}
+		op := ">"
+		if s.Desc != c.Before {
+			op = "<"
+		}
+
+		v := b.arg(c.Values[i])
+		ors = append(ors, "("+strings.Join(append(eqs, fmt.Sprintf("%s %s %s", col, op, v)), " AND ")+")")
+		eqs = append(eqs, fmt.Sprintf("%s = %s", col, v))
+	}
+	op := ">"
+	if c.Before {
+		op = "<"
+	}
+	ors = append(ors, "("+strings.Join(append(eqs, fmt.Sprintf("id %s %s", op, b.arg(c.ID))), " AND ")+")")
+
+	b.and("(" + strings.Join(ors, " OR ") + ")")
+	return nil
+}
@@ -3,16 +3,33 @@ package store
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
 
 	"github.com/kott/go-service-example/pkg/services/articles"
 	"github.com/kott/go-service-example/pkg/utils/log"
 )
 
+// articleColumns are the columns scanned into an articles.Article, in scan order (see articleDest).
+// The tags of each article are aggregated into an array of their names.
+const articleColumns = `id, slug, title, body, created_at, updated_at, disabled_at, version, author_id, status, publish_at, expire_at, ` + tagsColumn
+
 const (
-	selectArticle      = `SELECT * FROM articles WHERE id=$1`
-	selectManyArticles = `SELECT * FROM articles LIMIT $1 OFFSET $2`
-	insertArticle      = `INSERT INTO articles (title, body, created_at, updated_at) VALUES ($1, $2, now(), now()) RETURNING id`
-	updateArticle      = `UPDATE articles SET title = $1, body = $2, updated_at = now() WHERE id = $3`
+	selectArticle      = `SELECT %s FROM articles WHERE id=$1 AND ($2 OR disabled_at IS NULL)`
+	selectManyArticles = `SELECT %s FROM %s%s%s LIMIT %s OFFSET %s`
+	insertArticle      = `INSERT INTO articles (title, body, author_id, publish_at, expire_at, slug, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, now(), now()) RETURNING id`
+	updateArticle      = `UPDATE articles SET title = $1, body = $2, author_id = $3, publish_at = $4, expire_at = $5, updated_at = now(), version = version + 1 WHERE id = $6 AND disabled_at IS NULL AND ($7 = 0 OR version = $7)`
+	patchArticle       = `UPDATE articles SET %s WHERE id = $%[2]d AND disabled_at IS NULL AND ($%[3]d = 0 OR version = $%[3]d)`
+	disableArticle     = `UPDATE articles SET disabled_at = now(), updated_at = now(), version = version + 1 WHERE id = $1 AND disabled_at IS NULL`
+
+	selectDisabledArticles = `SELECT ` + articleColumns + ` FROM articles WHERE disabled_at IS NOT NULL ORDER BY disabled_at DESC LIMIT $1 OFFSET $2`
+	restoreArticle         = `UPDATE articles SET disabled_at = NULL, updated_at = now(), version = version + 1 WHERE id = $1 AND disabled_at IS NOT NULL`
+	purgeArticle           = `DELETE FROM articles WHERE id = $1 AND disabled_at IS NOT NULL`
+	selectArticlesByIDs    = `SELECT ` + articleColumns + ` FROM articles WHERE id = ANY($1::uuid[])`
+	articleExists          = `SELECT EXISTS(SELECT 1 FROM articles WHERE id = $1)`
+	liveArticleExists      = `SELECT EXISTS(SELECT 1 FROM articles WHERE id = $1 AND disabled_at IS NULL)`
 )
 
 type articleRepo struct {
@@ -24,12 +41,14 @@ func New(conn *sql.DB) articles.Repo {
 	return &articleRepo{conn}
 }
 
-// Get retrieves the article with the given id
-func (r *articleRepo) Get(ctx context.Context, id string) (articles.Article, error) {
+// Get retrieves the article with the given id. Disabled articles are only returned when includeDisabled is set.
+// When fields are given only their columns are read (see selection).
+func (r *articleRepo) Get(ctx context.Context, id string, includeDisabled bool, fields []string) (articles.Article, error) {
 	var ar articles.Article
 
-	err := r.DB.QueryRow(selectArticle, id).
-		Scan(&ar.ID, &ar.Title, &ar.Body, &ar.CreatedAt, &ar.UpdatedAt, &ar.DisabledAt)
+	columns, dest := selection(fields)
+	err := r.DB.QueryRow(fmt.Sprintf(selectArticle, columns), id, includeDisabled).
+		Scan(dest(&ar)...)
 	if err != nil {
 		log.Info(ctx, "select article error: %s", err.Error())
 		return ar, articles.ErrArticleNotFound
@@ -38,11 +57,89 @@ func (r *articleRepo) Get(ctx context.Context, id string) (articles.Article, err
 	return ar, nil
 }
 
-// GetAll retrieves all articles within the limit and offset. Limit defaults to 25
-func (r *articleRepo) GetAll(ctx context.Context, limit, offset int) ([]articles.Article, error) {
+// GetAll retrieves the articles within the limit in the requested order. Pages start either at
+// the offset or, when a cursor is given, right after the article the cursor points to. A page
+// before a cursor is read in the reverse order, closest article first, and turned around. When
+// AsOf is set the articles are listed as they were at that time. When Fields are given only their
+// columns are read, along with the ones the articles are sorted by so the cursor can be built.
+func (r *articleRepo) GetAll(ctx context.Context, p articles.ListParams) ([]articles.Article, error) {
+	sort := p.Sort
+	if len(sort) == 0 {
+		sort = articles.DefaultSort
+	}
+
+	back := p.Cursor != nil && p.Cursor.Before
+	order, err := orderBy(sort, back)
+	if err != nil {
+		log.Warn(ctx, "unable to build article query: %s", err.Error())
+		return make([]articles.Article, 0), articles.ErrArticleQuery
+	}
+
+	b, from := listing(p)
+
+	offset := p.Offset
+	if p.Cursor != nil {
+		if err := b.afterCursor(sort, *p.Cursor); err != nil {
+			log.Warn(ctx, "unable to build article query: %s", err.Error())
+			return make([]articles.Article, 0), articles.ErrArticleQuery
+		}
+		offset = 0
+	}
+
+	sortFields := make([]string, len(sort))
+	for i, s := range sort {
+		sortFields[i] = s.Field
+	}
+	columns, dest := selection(p.Fields, sortFields...)
+
+	query := fmt.Sprintf(selectManyArticles, columns, from, b.conditions(), order, b.arg(p.Limit), b.arg(offset))
+	al, err := r.queryInto(ctx, dest, query, b.args...)
+	if back {
+		for i, j := 0, len(al)-1; i < j; i, j = i+1, j-1 {
+			al[i], al[j] = al[j], al[i]
+		}
+	}
+	return al, err
+}
+
+// listing starts the query of a listing with the table the articles are listed from and the conditions
+// which narrow them down, leaving out the paging
+func listing(p articles.ListParams) (*builder, string) {
+	b := &builder{}
+	from := b.source(p.AsOf)
+	b.and(fmt.Sprintf("(%s OR disabled_at IS NULL)", b.arg(p.IncludeDisabled)))
+	b.withTags(p.Tags, p.TagMatch)
+	if p.AuthorID != "" {
+		b.and(fmt.Sprintf("author_id = %s", b.arg(p.AuthorID)))
+	}
+	b.withStatuses(p.Statuses)
+	return b, from
+}
+
+// GetDisabled retrieves the soft-deleted articles, most recently disabled first
+func (r *articleRepo) GetDisabled(ctx context.Context, limit, offset int) ([]articles.Article, error) {
+	return r.query(ctx, selectDisabledArticles, limit, offset)
+}
+
+// GetMany retrieves the articles with the given ids, disabled or not, in no particular order
+func (r *articleRepo) GetMany(ctx context.Context, ids []string) ([]articles.Article, error) {
+	return r.query(ctx, selectArticlesByIDs, pq.Array(ids))
+}
+
+// articleDest lists where each of the articleColumns is scanned to
+func articleDest(ar *articles.Article) []interface{} {
+	return []interface{}{&ar.ID, &ar.Slug, &ar.Title, &ar.Body, &ar.CreatedAt, &ar.UpdatedAt, &ar.DisabledAt, &ar.Version, &ar.AuthorID, &ar.Status, &ar.PublishAt, &ar.ExpireAt, pq.Array(&ar.Tags)}
+}
+
+func (r *articleRepo) query(ctx context.Context, query string, args ...interface{}) ([]articles.Article, error) {
+	return r.queryInto(ctx, articleDest, query, args...)
+}
+
+// queryInto runs a query whose columns are scanned to where dest says
+func (r *articleRepo) queryInto(ctx context.Context, dest func(*articles.Article) []interface{}, query string, args ...interface{}) ([]articles.Article, error) {
 	al := make([]articles.Article, 0)
 
-	rows, err := r.DB.Query(selectManyArticles, limit, offset)
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
 		log.Warn(ctx, "unable to query db: %s", err.Error())
 		return al, articles.ErrArticleQuery
@@ -51,7 +148,7 @@ func (r *articleRepo) GetAll(ctx context.Context, limit, offset int) ([]articles
 
 	for rows.Next() {
 		var ar articles.Article
-		if err := rows.Scan(&ar.ID, &ar.Title, &ar.Body, &ar.CreatedAt, &ar.UpdatedAt, &ar.DisabledAt); err != nil {
+		if err := rows.Scan(dest(&ar)...); err != nil {
 			log.Error(ctx, "unable to scan db rows: %s", err.Error())
 			return al, articles.ErrArticleQuery
 		}
@@ -62,24 +159,231 @@ func (r *articleRepo) GetAll(ctx context.Context, limit, offset int) ([]articles
 	return al, nil
 }
 
-// Create sets the title and body in a new db record
+// Create sets the title, body, author, schedule and tags in a new db record along with a slug derived from the
+// title, and records it as the first revision
 func (r *articleRepo) Create(ctx context.Context, ar articles.ArticleCreateUpdate) (string, error) {
 	var id string
-	if err := r.DB.QueryRow(insertArticle, ar.Title, ar.Body).Scan(&id); err != nil {
-		log.Error(ctx, "unable to create article: %s", err.Error())
-		return "", articles.ErrArticleCreate
+	err := r.inTx(ctx, articles.ErrArticleCreate, func(tx *sql.Tx) (err error) {
+		id, err = createArticle(ctx, tx, ar)
+		return err
+	})
+	if err != nil {
+		return "", err
 	}
 
 	log.Info(ctx, "created article with id=%s", id)
 	return id, nil
 }
 
-// Update sets the title and body on an existing record on the requested version of the row
-func (r *articleRepo) Update(ctx context.Context, ar articles.ArticleCreateUpdate, id string) error {
-	_, err := r.DB.Exec(updateArticle, ar.Title, ar.Body, id)
+// createArticle inserts the article, claims its slug, tags it and records its first revision within the transaction
+func createArticle(ctx context.Context, tx *sql.Tx, ar articles.ArticleCreateUpdate) (string, error) {
+	slug, _, err := pickSlug(tx, "", "", ar.Title)
 	if err != nil {
+		log.Error(ctx, "unable to pick a slug for article: %s", err.Error())
+		return "", articles.ErrArticleCreate
+	}
+
+	var id string
+	if err := tx.QueryRow(insertArticle, ar.Title, ar.Body, ar.AuthorID, ar.PublishAt, ar.ExpireAt, slug).Scan(&id); err != nil {
+		if isForeignKeyViolation(err) {
+			return "", articles.ErrArticleAuthorNotFound
+		}
+		if isCheckViolation(err) {
+			return "", articles.ErrInvalidSchedule
+		}
+		log.Error(ctx, "unable to create article: %s", err.Error())
+		return "", articles.ErrArticleCreate
+	}
+	if _, err := tx.Exec(insertSlug, slug, id); err != nil {
+		log.Error(ctx, "unable to claim slug %s for article (%s): %s", slug, id, err.Error())
+		return "", articles.ErrArticleCreate
+	}
+	if err := setTags(tx, id, ar.Tags); err != nil {
+		log.Error(ctx, "unable to tag article (%s): %s", id, err.Error())
+		return "", articles.ErrArticleCreate
+	}
+	if err := addRevision(ctx, tx, id); err != nil {
+		log.Error(ctx, "unable to record revision of article (%s): %s", id, err.Error())
+		return "", articles.ErrArticleCreate
+	}
+	return id, nil
+}
+
+// Update sets the title, body, author, schedule and tags on an existing record on the requested version of the row.
+// A version of 0 skips the version check.
+func (r *articleRepo) Update(ctx context.Context, ar articles.ArticleCreateUpdate, id string, version int) error {
+	return r.inTx(ctx, articles.ErrArticleUpdate, func(tx *sql.Tx) error {
+		return updateArticleTx(ctx, tx, ar, id, version)
+	})
+}
+
+// updateArticleTx runs Update within the transaction
+func updateArticleTx(ctx context.Context, tx *sql.Tx, ar articles.ArticleCreateUpdate, id string, version int) error {
+	return modifyArticle(ctx, tx, id, &ar.Title, &ar.Tags, updateArticle, ar.Title, ar.Body, ar.AuthorID, ar.PublishAt, ar.ExpireAt, id, version)
+}
+
+// Patch sets only the columns present in the patch on the requested version of the row.
+// A version of 0 skips the version check.
+func (r *articleRepo) Patch(ctx context.Context, p articles.ArticlePatch, id string, version int) error {
+	var sets []string
+	var args []interface{}
+	if p.Title != nil {
+		args = append(args, *p.Title)
+		sets = append(sets, fmt.Sprintf("title = $%d", len(args)))
+	}
+	if p.Body != nil {
+		args = append(args, *p.Body)
+		sets = append(sets, fmt.Sprintf("body = $%d", len(args)))
+	}
+	if p.PublishAt != nil {
+		args = append(args, *p.PublishAt)
+		sets = append(sets, fmt.Sprintf("publish_at = $%d", len(args)))
+	}
+	if p.ExpireAt != nil {
+		args = append(args, *p.ExpireAt)
+		sets = append(sets, fmt.Sprintf("expire_at = $%d", len(args)))
+	}
+	sets = append(sets, "updated_at = now()", "version = version + 1")
+	args = append(args, id, version)
+
+	query := fmt.Sprintf(patchArticle, strings.Join(sets, ", "), len(args)-1, len(args))
+	return r.inTx(ctx, articles.ErrArticleUpdate, func(tx *sql.Tx) error {
+		return modifyArticle(ctx, tx, id, p.Title, p.Tags, query, args...)
+	})
+}
+
+// modifyArticle runs an update of the article's content, moves it to the slug of its title and replaces its tags
+// unless they are nil, and records the result as a new revision, all within the same transaction.
+func modifyArticle(ctx context.Context, tx *sql.Tx, id string, title *string, tags *[]string, query string, args ...interface{}) error {
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return articles.ErrArticleAuthorNotFound
+		}
+		if isCheckViolation(err) {
+			return articles.ErrInvalidSchedule
+		}
 		log.Error(ctx, "unable to update article (%s): %s", id, err.Error())
 		return articles.ErrArticleUpdate
 	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Error(ctx, "unable to update article (%s): %s", id, err.Error())
+		return articles.ErrArticleUpdate
+	}
+	if n == 0 {
+		return versionMismatchErr(ctx, tx, id)
+	}
+
+	if title != nil {
+		if err := setSlug(tx, id, *title); err != nil {
+			log.Error(ctx, "unable to update slug of article (%s): %s", id, err.Error())
+			return articles.ErrArticleUpdate
+		}
+	}
+
+	if tags != nil {
+		if err := setTags(tx, id, *tags); err != nil {
+			log.Error(ctx, "unable to tag article (%s): %s", id, err.Error())
+			return articles.ErrArticleUpdate
+		}
+	}
+
+	if err := addRevision(ctx, tx, id); err != nil {
+		log.Error(ctx, "unable to record revision of article (%s): %s", id, err.Error())
+		return articles.ErrArticleUpdate
+	}
+	return nil
+}
+
+// Delete soft-deletes the article by setting disabled_at. Articles which are already disabled are treated as not found.
+func (r *articleRepo) Delete(ctx context.Context, id string) error {
+	res, err := r.DB.Exec(disableArticle, id)
+	if err != nil {
+		log.Error(ctx, "unable to delete article (%s): %s", id, err.Error())
+		return articles.ErrArticleDelete
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Error(ctx, "unable to delete article (%s): %s", id, err.Error())
+		return articles.ErrArticleDelete
+	}
+	if n == 0 {
+		return articles.ErrArticleNotFound
+	}
+
+	log.Info(ctx, "disabled article with id=%s", id)
 	return nil
 }
+
+// Restore clears disabled_at on an article that is currently in the trash
+func (r *articleRepo) Restore(ctx context.Context, id string) error {
+	res, err := r.DB.Exec(restoreArticle, id)
+	if err != nil {
+		log.Error(ctx, "unable to restore article (%s): %s", id, err.Error())
+		return articles.ErrArticleRestore
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Error(ctx, "unable to restore article (%s): %s", id, err.Error())
+		return articles.ErrArticleRestore
+	}
+	if n == 0 {
+		return r.notDisabledErr(ctx, id)
+	}
+
+	log.Info(ctx, "restored article with id=%s", id)
+	return nil
+}
+
+// Purge removes the row of an article that is currently in the trash
+func (r *articleRepo) Purge(ctx context.Context, id string) error {
+	res, err := r.DB.Exec(purgeArticle, id)
+	if err != nil {
+		log.Error(ctx, "unable to purge article (%s): %s", id, err.Error())
+		return articles.ErrArticlePurge
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Error(ctx, "unable to purge article (%s): %s", id, err.Error())
+		return articles.ErrArticlePurge
+	}
+	if n == 0 {
+		return r.notDisabledErr(ctx, id)
+	}
+
+	log.Info(ctx, "purged article with id=%s", id)
+	return nil
+}
+
+// notDisabledErr determines why a trash operation did not touch any rows:
+// either the article does not exist or it has not been disabled.
+func (r *articleRepo) notDisabledErr(ctx context.Context, id string) error {
+	var exists bool
+	if err := r.DB.QueryRow(articleExists, id).Scan(&exists); err != nil {
+		log.Info(ctx, "article exists error: %s", err.Error())
+		return articles.ErrArticleNotFound
+	}
+	if exists {
+		return articles.ErrArticleNotDisabled
+	}
+	return articles.ErrArticleNotFound
+}
+
+// versionMismatchErr determines why an update did not touch any rows:
+// either the article does not exist (or is disabled) or the version did not match.
+func versionMismatchErr(ctx context.Context, q querier, id string) error {
+	var exists bool
+	if err := q.QueryRow(liveArticleExists, id).Scan(&exists); err != nil {
+		log.Info(ctx, "article exists error: %s", err.Error())
+		return articles.ErrArticleNotFound
+	}
+	if exists {
+		return articles.ErrArticleVersionMismatch
+	}
+	return articles.ErrArticleNotFound
+}
@@ -5,22 +5,24 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/kott/go-service-example/pkg/services/articles"
 )
 
 func TestArticleRepoGet(t *testing.T) {
-	columns := []string{"id", "title", "body", "created_at", "updated_at", "disabled_at"}
+	columns := []string{"id", "slug", "title", "body", "created_at", "updated_at", "disabled_at", "version", "author_id", "status", "publish_at", "expire_at", "tags"}
 	id := uuid.New().String()
 	now := time.Now()
-	mockResult := []driver.Value{id, "title", "body", now, now, now}
+	mockResult := []driver.Value{id, "title", "title", "body", now, now, now, 1, nil, "published", nil, nil, "{db,go}"}
 
 	tests := map[string]struct {
 		expectQueryArgs        []driver.Value
@@ -31,7 +33,7 @@ func TestArticleRepoGet(t *testing.T) {
 		err                    error
 	}{
 		"Happy path": {
-			expectQueryArgs:        []driver.Value{id},
+			expectQueryArgs:        []driver.Value{id, false},
 			expectQueryResultRows:  []*sqlmock.Rows{sqlmock.NewRows(columns).AddRow(mockResult...)},
 			expectQueryResultError: nil,
 			input:                  id,
@@ -39,7 +41,7 @@ func TestArticleRepoGet(t *testing.T) {
 			err:                    nil,
 		},
 		"Unknown DB error": {
-			expectQueryArgs:        []driver.Value{id},
+			expectQueryArgs:        []driver.Value{id, false},
 			expectQueryResultRows:  []*sqlmock.Rows{sqlmock.NewRows(columns).AddRow(mockResult...)},
 			expectQueryResultError: errors.New("some-db-error"),
 			input:                  id,
@@ -47,7 +49,7 @@ func TestArticleRepoGet(t *testing.T) {
 			err:                    articles.ErrArticleNotFound,
 		},
 		"Not found error": {
-			expectQueryArgs:        []driver.Value{"fake-id"},
+			expectQueryArgs:        []driver.Value{"fake-id", false},
 			expectQueryResultRows:  []*sqlmock.Rows{sqlmock.NewRows(columns).AddRow(mockResult...)},
 			expectQueryResultError: sql.ErrNoRows,
 			input:                  "fake-id",
@@ -61,10 +63,10 @@ func TestArticleRepoGet(t *testing.T) {
 			db, mock, _ := sqlmock.New()
 			defer db.Close()
 
-			mock.ExpectQuery(regexp.QuoteMeta(selectArticle)).WithArgs(test.expectQueryArgs...).WillReturnError(test.expectQueryResultError).WillReturnRows(test.expectQueryResultRows...)
+			mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(selectArticle, articleColumns))).WithArgs(test.expectQueryArgs...).WillReturnError(test.expectQueryResultError).WillReturnRows(test.expectQueryResultRows...)
 
 			repo := New(db)
-			response, err := repo.Get(context.Background(), test.input)
+			response, err := repo.Get(context.Background(), test.input, false, nil)
 
 			assert.Equal(t, test.err, err)
 			assert.Equal(t, test.expect.ID, response.ID)
@@ -72,36 +74,156 @@ func TestArticleRepoGet(t *testing.T) {
 	}
 }
 
+func TestArticleRepoGetAll(t *testing.T) {
+	columns := []string{"id", "slug", "title", "body", "created_at", "updated_at", "disabled_at", "version", "author_id", "status", "publish_at", "expire_at", "tags"}
+	id := uuid.New().String()
+	now := time.Now()
+
+	tests := map[string]struct {
+		input       articles.ListParams
+		expectQuery string
+		expectArgs  []driver.Value
+	}{
+		"Offset": {
+			input:       articles.ListParams{Limit: 25, Offset: 50},
+			expectQuery: `SELECT id, slug, title, body, created_at, updated_at, disabled_at, version, author_id, status, publish_at, expire_at, ARRAY(SELECT name FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id ORDER BY name) AS tags FROM articles WHERE ($1 OR disabled_at IS NULL) ORDER BY created_at, id LIMIT $2 OFFSET $3`,
+			expectArgs:  []driver.Value{false, 25, 50},
+		},
+		"Cursor": {
+			input:       articles.ListParams{Limit: 25, Offset: 50, Cursor: &articles.Cursor{Sort: "createdAt", Values: []string{"2021-04-01T12:00:00Z"}, ID: id}, IncludeDisabled: true},
+			expectQuery: `SELECT id, slug, title, body, created_at, updated_at, disabled_at, version, author_id, status, publish_at, expire_at, ARRAY(SELECT name FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id ORDER BY name) AS tags FROM articles WHERE ($1 OR disabled_at IS NULL) AND ((created_at > $2) OR (created_at = $2 AND id > $3)) ORDER BY created_at, id LIMIT $4 OFFSET $5`,
+			expectArgs:  []driver.Value{true, "2021-04-01T12:00:00Z", id, 25, 0},
+		},
+		"As of": {
+			input:       articles.ListParams{Limit: 25, AsOf: &now},
+			expectQuery: `SELECT id, slug, title, body, created_at, updated_at, disabled_at, version, author_id, status, publish_at, expire_at, ARRAY(SELECT name FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id ORDER BY name) AS tags FROM (SELECT (jsonb_populate_record(NULL::articles, data)).* FROM article_history WHERE valid_from <= $1 AND (valid_to IS NULL OR valid_to > $1)) AS articles WHERE ($2 OR disabled_at IS NULL) ORDER BY created_at, id LIMIT $3 OFFSET $4`,
+			expectArgs:  []driver.Value{now, false, 25, 0},
+		},
+		"Any tag": {
+			input:       articles.ListParams{Limit: 25, Tags: []string{"db", "go"}},
+			expectQuery: `SELECT id, slug, title, body, created_at, updated_at, disabled_at, version, author_id, status, publish_at, expire_at, ARRAY(SELECT name FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id ORDER BY name) AS tags FROM articles WHERE ($1 OR disabled_at IS NULL) AND EXISTS (SELECT 1 FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id AND name = ANY($2)) ORDER BY created_at, id LIMIT $3 OFFSET $4`,
+			expectArgs:  []driver.Value{false, pq.Array([]string{"db", "go"}), 25, 0},
+		},
+		"All tags": {
+			input:       articles.ListParams{Limit: 25, Tags: []string{"db", "go"}, TagMatch: articles.TagMatchAll},
+			expectQuery: `SELECT id, slug, title, body, created_at, updated_at, disabled_at, version, author_id, status, publish_at, expire_at, ARRAY(SELECT name FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id ORDER BY name) AS tags FROM articles WHERE ($1 OR disabled_at IS NULL) AND (SELECT COUNT(*) FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id AND name = ANY($2)) = $3 ORDER BY created_at, id LIMIT $4 OFFSET $5`,
+			expectArgs:  []driver.Value{false, pq.Array([]string{"db", "go"}), 2, 25, 0},
+		},
+		"Author": {
+			input:       articles.ListParams{Limit: 25, AuthorID: id},
+			expectQuery: `SELECT id, slug, title, body, created_at, updated_at, disabled_at, version, author_id, status, publish_at, expire_at, ARRAY(SELECT name FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id ORDER BY name) AS tags FROM articles WHERE ($1 OR disabled_at IS NULL) AND author_id = $2 ORDER BY created_at, id LIMIT $3 OFFSET $4`,
+			expectArgs:  []driver.Value{false, id, 25, 0},
+		},
+		"Statuses": {
+			input:       articles.ListParams{Limit: 25, Statuses: []articles.Status{articles.StatusDraft, articles.StatusInReview}},
+			expectQuery: `SELECT id, slug, title, body, created_at, updated_at, disabled_at, version, author_id, status, publish_at, expire_at, ARRAY(SELECT name FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id ORDER BY name) AS tags FROM articles WHERE ($1 OR disabled_at IS NULL) AND status = ANY($2) ORDER BY created_at, id LIMIT $3 OFFSET $4`,
+			expectArgs:  []driver.Value{false, pq.Array([]string{"draft", "in_review"}), 25, 0},
+		},
+		"Sorted cursor": {
+			input: articles.ListParams{
+				Limit:  10,
+				Sort:   []articles.SortField{{Field: articles.SortUpdatedAt, Desc: true}, {Field: articles.SortTitle}},
+				Cursor: &articles.Cursor{Sort: "-updatedAt,title", Values: []string{"2021-04-01T12:00:00Z", "some-title"}, ID: id},
+			},
+			expectQuery: `SELECT id, slug, title, body, created_at, updated_at, disabled_at, version, author_id, status, publish_at, expire_at, ARRAY(SELECT name FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id ORDER BY name) AS tags FROM articles WHERE ($1 OR disabled_at IS NULL) AND ((updated_at < $2) OR (updated_at = $2 AND title > $3) OR (updated_at = $2 AND title = $3 AND id > $4)) ORDER BY updated_at DESC, title, id LIMIT $5 OFFSET $6`,
+			expectArgs:  []driver.Value{false, "2021-04-01T12:00:00Z", "some-title", id, 10, 0},
+		},
+		"Sorted cursor before": {
+			input: articles.ListParams{
+				Limit:  10,
+				Sort:   []articles.SortField{{Field: articles.SortUpdatedAt, Desc: true}, {Field: articles.SortTitle}},
+				Cursor: &articles.Cursor{Sort: "-updatedAt,title", Values: []string{"2021-04-01T12:00:00Z", "some-title"}, ID: id, Before: true},
+			},
+			expectQuery: `SELECT id, slug, title, body, created_at, updated_at, disabled_at, version, author_id, status, publish_at, expire_at, ARRAY(SELECT name FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id ORDER BY name) AS tags FROM articles WHERE ($1 OR disabled_at IS NULL) AND ((updated_at > $2) OR (updated_at = $2 AND title < $3) OR (updated_at = $2 AND title = $3 AND id < $4)) ORDER BY updated_at, title DESC, id DESC LIMIT $5 OFFSET $6`,
+			expectArgs:  []driver.Value{false, "2021-04-01T12:00:00Z", "some-title", id, 10, 0},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			rows := sqlmock.NewRows(columns).AddRow(id, "title", "title", "body", now, now, nil, 1, nil, "published", nil, nil, "{}")
+			mock.ExpectQuery(regexp.QuoteMeta(test.expectQuery)).WithArgs(test.expectArgs...).WillReturnRows(rows)
+
+			repo := New(db)
+			response, err := repo.GetAll(context.Background(), test.input)
+
+			assert.NoError(t, err)
+			assert.Len(t, response, 1)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestArticleRepoCreate(t *testing.T) {
 	columns := []string{"id"}
 	id := uuid.New().String()
+	authorID := uuid.New().String()
 	title := "some-title"
 	body := "some-body"
+	publishAt := time.Date(2021, 4, 2, 12, 0, 0, 0, time.UTC)
+	expireAt := publishAt.Add(-time.Hour)
 
 	tests := map[string]struct {
 		expectQueryArgs        []driver.Value
 		expectQueryResultRows  []*sqlmock.Rows
 		expectQueryResultError error
+		expectRevisionError    error
 		input                  articles.ArticleCreateUpdate
 		expect                 string
 		err                    error
 	}{
 		"Happy path": {
-			expectQueryArgs:        []driver.Value{title, body},
+			expectQueryArgs:        []driver.Value{title, body, nil, nil, nil},
 			expectQueryResultRows:  []*sqlmock.Rows{sqlmock.NewRows(columns).AddRow(id)},
 			expectQueryResultError: nil,
 			input:                  articles.ArticleCreateUpdate{Title: title, Body: body},
 			expect:                 id,
 			err:                    nil,
 		},
+		"Tagged": {
+			expectQueryArgs:        []driver.Value{title, body, nil, nil, nil},
+			expectQueryResultRows:  []*sqlmock.Rows{sqlmock.NewRows(columns).AddRow(id)},
+			expectQueryResultError: nil,
+			input:                  articles.ArticleCreateUpdate{Title: title, Body: body, Tags: []string{"db", "go"}},
+			expect:                 id,
+			err:                    nil,
+		},
 		"Create error": {
-			expectQueryArgs:        []driver.Value{title, body},
+			expectQueryArgs:        []driver.Value{title, body, nil, nil, nil},
 			expectQueryResultRows:  []*sqlmock.Rows{sqlmock.NewRows(columns).AddRow(id)},
 			expectQueryResultError: errors.New("some-db-error"),
 			input:                  articles.ArticleCreateUpdate{Title: title, Body: body},
 			expect:                 "",
 			err:                    articles.ErrArticleCreate,
 		},
+		"Unknown author": {
+			expectQueryArgs:        []driver.Value{title, body, authorID, nil, nil},
+			expectQueryResultRows:  []*sqlmock.Rows{sqlmock.NewRows(columns).AddRow(id)},
+			expectQueryResultError: &pq.Error{Code: "23503"},
+			input:                  articles.ArticleCreateUpdate{Title: title, Body: body, AuthorID: &authorID},
+			expect:                 "",
+			err:                    articles.ErrArticleAuthorNotFound,
+		},
+		"Expires before publishing": {
+			expectQueryArgs:        []driver.Value{title, body, nil, publishAt, expireAt},
+			expectQueryResultRows:  []*sqlmock.Rows{sqlmock.NewRows(columns).AddRow(id)},
+			expectQueryResultError: &pq.Error{Code: "23514"},
+			input:                  articles.ArticleCreateUpdate{Title: title, Body: body, PublishAt: &publishAt, ExpireAt: &expireAt},
+			expect:                 "",
+			err:                    articles.ErrInvalidSchedule,
+		},
+		"Revision error": {
+			expectQueryArgs:        []driver.Value{title, body, nil, nil, nil},
+			expectQueryResultRows:  []*sqlmock.Rows{sqlmock.NewRows(columns).AddRow(id)},
+			expectQueryResultError: nil,
+			expectRevisionError:    errors.New("some-db-error"),
+			input:                  articles.ArticleCreateUpdate{Title: title, Body: body},
+			expect:                 "",
+			err:                    articles.ErrArticleCreate,
+		},
 	}
 
 	for testName, test := range tests {
@@ -109,13 +231,277 @@ func TestArticleRepoCreate(t *testing.T) {
 			db, mock, _ := sqlmock.New()
 			defer db.Close()
 
-			mock.ExpectQuery(regexp.QuoteMeta(insertArticle)).WithArgs(test.expectQueryArgs...).WillReturnError(test.expectQueryResultError).WillReturnRows(test.expectQueryResultRows...)
+			mock.ExpectBegin()
+			expectPickSlug(mock, title)
+			mock.ExpectQuery(regexp.QuoteMeta(insertArticle)).WithArgs(append(test.expectQueryArgs, title)...).WillReturnError(test.expectQueryResultError).WillReturnRows(test.expectQueryResultRows...)
+			if test.expectQueryResultError == nil {
+				mock.ExpectExec(regexp.QuoteMeta(insertSlug)).WithArgs(title, id).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectSetTags(mock, id, test.input.Tags)
+				mock.ExpectExec(regexp.QuoteMeta(insertRevision)).WithArgs(id, "").WillReturnError(test.expectRevisionError).WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			if test.err == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
 
 			repo := New(db)
 			response, err := repo.Create(context.Background(), test.input)
 
 			assert.Equal(t, test.err, err)
 			assert.Equal(t, test.expect, response)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// expectPickSlug expects the statements which pick a slug for a title whose slug is not taken yet
+func expectPickSlug(mock sqlmock.Sqlmock, slug string) {
+	mock.ExpectExec(regexp.QuoteMeta(lockSlug)).WithArgs(slug).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(selectSlugsForBase)).WithArgs(slug, slug+"-%").WillReturnRows(sqlmock.NewRows([]string{"slug", "article_id"}))
+}
+
+// expectKeepSlug expects the statements which find that the current slug of an article still matches its title
+func expectKeepSlug(mock sqlmock.Sqlmock, id, slug string) {
+	mock.ExpectQuery(regexp.QuoteMeta(selectArticleSlug)).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow(slug))
+	mock.ExpectExec(regexp.QuoteMeta(updateArticleSlug)).WithArgs(slug, id).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectSetTags expects the statements which replace the tags of an article
+func expectSetTags(mock sqlmock.Sqlmock, id string, tags []string) {
+	mock.ExpectExec(regexp.QuoteMeta(deleteArticleTags)).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+	if len(tags) == 0 {
+		return
+	}
+	mock.ExpectExec(regexp.QuoteMeta(insertTags)).WithArgs(pq.Array(tags)).WillReturnResult(sqlmock.NewResult(0, int64(len(tags))))
+	mock.ExpectExec(regexp.QuoteMeta(insertArticleTags)).WithArgs(id, pq.Array(tags)).WillReturnResult(sqlmock.NewResult(0, int64(len(tags))))
+}
+
+func TestArticleRepoUpdate(t *testing.T) {
+	id := uuid.New().String()
+	authorID := uuid.New().String()
+	title := "some-title"
+	body := "some-body"
+
+	tests := map[string]struct {
+		version          int
+		expectExecResult driver.Result
+		expectExecError  error
+		expectExistsRows *sqlmock.Rows
+		err              error
+	}{
+		"Happy path": {
+			version:          3,
+			expectExecResult: sqlmock.NewResult(0, 1),
+			err:              nil,
+		},
+		"Unconditional update": {
+			version:          0,
+			expectExecResult: sqlmock.NewResult(0, 1),
+			err:              nil,
+		},
+		"Stale version": {
+			version:          2,
+			expectExecResult: sqlmock.NewResult(0, 0),
+			expectExistsRows: sqlmock.NewRows([]string{"exists"}).AddRow(true),
+			err:              articles.ErrArticleVersionMismatch,
+		},
+		"Missing or disabled": {
+			version:          2,
+			expectExecResult: sqlmock.NewResult(0, 0),
+			expectExistsRows: sqlmock.NewRows([]string{"exists"}).AddRow(false),
+			err:              articles.ErrArticleNotFound,
+		},
+		"Update error": {
+			version:         0,
+			expectExecError: errors.New("some-db-error"),
+			err:             articles.ErrArticleUpdate,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(updateArticle)).WithArgs(title, body, authorID, nil, nil, id, test.version).WillReturnError(test.expectExecError).WillReturnResult(test.expectExecResult)
+			if test.expectExistsRows != nil {
+				mock.ExpectQuery(regexp.QuoteMeta(liveArticleExists)).WithArgs(id).WillReturnRows(test.expectExistsRows)
+			}
+			if test.err == nil {
+				expectKeepSlug(mock, id, title)
+				expectSetTags(mock, id, []string{"go"})
+				mock.ExpectExec(regexp.QuoteMeta(insertRevision)).WithArgs(id, "").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			repo := New(db)
+			err := repo.Update(context.Background(), articles.ArticleCreateUpdate{Title: title, Body: body, Tags: []string{"go"}, AuthorID: &authorID}, id, test.version)
+
+			assert.Equal(t, test.err, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestArticleRepoPatch(t *testing.T) {
+	id := uuid.New().String()
+	title := "some-title"
+	body := "some-body"
+	at := time.Date(2021, 4, 2, 12, 0, 0, 0, time.UTC)
+	publishAt := &at
+	var noExpiry *time.Time
+
+	tests := map[string]struct {
+		input        articles.ArticlePatch
+		version      int
+		expectQuery  string
+		expectArgs   []driver.Value
+		expectResult driver.Result
+		err          error
+	}{
+		"Title only": {
+			input:        articles.ArticlePatch{Title: &title},
+			version:      2,
+			expectQuery:  `UPDATE articles SET title = $1, updated_at = now(), version = version + 1 WHERE id = $2 AND disabled_at IS NULL AND ($3 = 0 OR version = $3)`,
+			expectArgs:   []driver.Value{title, id, 2},
+			expectResult: sqlmock.NewResult(0, 1),
+		},
+		"Title and body": {
+			input:        articles.ArticlePatch{Title: &title, Body: &body},
+			version:      0,
+			expectQuery:  `UPDATE articles SET title = $1, body = $2, updated_at = now(), version = version + 1 WHERE id = $3 AND disabled_at IS NULL AND ($4 = 0 OR version = $4)`,
+			expectArgs:   []driver.Value{title, body, id, 0},
+			expectResult: sqlmock.NewResult(0, 1),
+		},
+		"Tags only": {
+			input:        articles.ArticlePatch{Tags: &[]string{"go"}},
+			version:      0,
+			expectQuery:  `UPDATE articles SET updated_at = now(), version = version + 1 WHERE id = $1 AND disabled_at IS NULL AND ($2 = 0 OR version = $2)`,
+			expectArgs:   []driver.Value{id, 0},
+			expectResult: sqlmock.NewResult(0, 1),
+		},
+		"Schedule": {
+			input:        articles.ArticlePatch{PublishAt: &publishAt, ExpireAt: &noExpiry},
+			version:      0,
+			expectQuery:  `UPDATE articles SET publish_at = $1, expire_at = $2, updated_at = now(), version = version + 1 WHERE id = $3 AND disabled_at IS NULL AND ($4 = 0 OR version = $4)`,
+			expectArgs:   []driver.Value{*publishAt, nil, id, 0},
+			expectResult: sqlmock.NewResult(0, 1),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(test.expectQuery)).WithArgs(test.expectArgs...).WillReturnResult(test.expectResult)
+			if test.input.Title != nil {
+				expectKeepSlug(mock, id, title)
+			}
+			if test.input.Tags != nil {
+				expectSetTags(mock, id, *test.input.Tags)
+			}
+			mock.ExpectExec(regexp.QuoteMeta(insertRevision)).WithArgs(id, "").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			repo := New(db)
+			err := repo.Patch(context.Background(), test.input, id, test.version)
+
+			assert.Equal(t, test.err, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestArticleRepoDelete(t *testing.T) {
+	id := uuid.New().String()
+
+	tests := map[string]struct {
+		expectExecResult driver.Result
+		expectExecError  error
+		err              error
+	}{
+		"Happy path": {
+			expectExecResult: sqlmock.NewResult(0, 1),
+			expectExecError:  nil,
+			err:              nil,
+		},
+		"Already disabled or missing": {
+			expectExecResult: sqlmock.NewResult(0, 0),
+			expectExecError:  nil,
+			err:              articles.ErrArticleNotFound,
+		},
+		"Delete error": {
+			expectExecResult: nil,
+			expectExecError:  errors.New("some-db-error"),
+			err:              articles.ErrArticleDelete,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(disableArticle)).WithArgs(id).WillReturnError(test.expectExecError).WillReturnResult(test.expectExecResult)
+
+			repo := New(db)
+			err := repo.Delete(context.Background(), id)
+
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestArticleRepoRestore(t *testing.T) {
+	id := uuid.New().String()
+
+	tests := map[string]struct {
+		expectExecResult driver.Result
+		expectExecError  error
+		expectExistsRows *sqlmock.Rows
+		err              error
+	}{
+		"Happy path": {
+			expectExecResult: sqlmock.NewResult(0, 1),
+			err:              nil,
+		},
+		"Not in trash": {
+			expectExecResult: sqlmock.NewResult(0, 0),
+			expectExistsRows: sqlmock.NewRows([]string{"exists"}).AddRow(true),
+			err:              articles.ErrArticleNotDisabled,
+		},
+		"Missing": {
+			expectExecResult: sqlmock.NewResult(0, 0),
+			expectExistsRows: sqlmock.NewRows([]string{"exists"}).AddRow(false),
+			err:              articles.ErrArticleNotFound,
+		},
+		"Restore error": {
+			expectExecError: errors.New("some-db-error"),
+			err:             articles.ErrArticleRestore,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(restoreArticle)).WithArgs(id).WillReturnError(test.expectExecError).WillReturnResult(test.expectExecResult)
+			if test.expectExistsRows != nil {
+				mock.ExpectQuery(regexp.QuoteMeta(articleExists)).WithArgs(id).WillReturnRows(test.expectExistsRows)
+			}
+
+			repo := New(db)
+			err := repo.Restore(context.Background(), id)
+
+			assert.Equal(t, test.err, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
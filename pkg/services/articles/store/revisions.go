@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	rcontext "github.com/kott/go-service-example/pkg/utils/context"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+const (
+	// revisionColumns are the columns scanned into an articles.Revision, in scan order
+	revisionColumns = `article_id, revision, title, body, COALESCE(request_id, ''), created_at`
+
+	// insertRevision snapshots the current title and body of an article as its next revision
+	insertRevision = `INSERT INTO article_revisions (article_id, revision, title, body, request_id, created_at) ` +
+		`SELECT id, COALESCE((SELECT MAX(revision) FROM article_revisions WHERE article_id = $1), 0) + 1, title, body, NULLIF($2, ''), now() ` +
+		`FROM articles WHERE id = $1`
+	// visibleArticleExists checks the article whose history is read: like the article itself, the history of an
+	// unpublished article is only served when asked for
+	visibleArticleExists = `SELECT EXISTS(SELECT 1 FROM articles WHERE id = $1 AND disabled_at IS NULL AND ($2 OR status = 'published'))`
+
+	selectRevisions = `SELECT ` + revisionColumns + ` FROM article_revisions WHERE article_id = $1 ORDER BY revision LIMIT $2 OFFSET $3`
+	selectRevision  = `SELECT ` + revisionColumns + ` FROM article_revisions WHERE article_id = $1 AND revision = $2`
+)
+
+// addRevision appends the current state of the article to its history. It is meant to be
+// run in the same transaction as the write which produced that state.
+func addRevision(ctx context.Context, q querier, id string) error {
+	_, err := q.Exec(insertRevision, id, rcontext.GetReqID(ctx))
+	return err
+}
+
+// GetRevisions retrieves the revisions of a live article, oldest first. Unless includeUnpublished is set the
+// article has to be published.
+func (r *articleRepo) GetRevisions(ctx context.Context, id string, includeUnpublished bool, limit, offset int) ([]articles.Revision, error) {
+	revs := make([]articles.Revision, 0)
+
+	var exists bool
+	if err := r.DB.QueryRow(visibleArticleExists, id, includeUnpublished).Scan(&exists); err != nil || !exists {
+		return revs, articles.ErrArticleNotFound
+	}
+
+	rows, err := r.DB.Query(selectRevisions, id, limit, offset)
+	if err != nil {
+		log.Warn(ctx, "unable to query revisions: %s", err.Error())
+		return revs, articles.ErrArticleQuery
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rev articles.Revision
+		if err := rows.Scan(&rev.ArticleID, &rev.Revision, &rev.Title, &rev.Body, &rev.RequestID, &rev.CreatedAt); err != nil {
+			log.Error(ctx, "unable to scan db rows: %s", err.Error())
+			return revs, articles.ErrArticleQuery
+		}
+
+		revs = append(revs, rev)
+	}
+
+	return revs, nil
+}
+
+// GetRevision retrieves a single revision of a live article, which has to be published unless includeUnpublished is set
+func (r *articleRepo) GetRevision(ctx context.Context, id string, includeUnpublished bool, revision int) (articles.Revision, error) {
+	var rev articles.Revision
+
+	var exists bool
+	if err := r.DB.QueryRow(visibleArticleExists, id, includeUnpublished).Scan(&exists); err != nil || !exists {
+		return rev, articles.ErrArticleNotFound
+	}
+
+	err := r.DB.QueryRow(selectRevision, id, revision).
+		Scan(&rev.ArticleID, &rev.Revision, &rev.Title, &rev.Body, &rev.RequestID, &rev.CreatedAt)
+	if err != nil {
+		log.Info(ctx, "select revision error: %s", err.Error())
+		return articles.Revision{}, articles.ErrRevisionNotFound
+	}
+
+	return rev, nil
+}
@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	rcontext "github.com/kott/go-service-example/pkg/utils/context"
+)
+
+func TestArticleRepoCreateRecordsRequestID(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	id := uuid.New().String()
+	ctx := rcontext.SetReqID(context.Background(), "some-request-id")
+
+	mock.ExpectBegin()
+	expectPickSlug(mock, "title")
+	mock.ExpectQuery(regexp.QuoteMeta(insertArticle)).WithArgs("title", "body", nil, nil, nil, "title").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	mock.ExpectExec(regexp.QuoteMeta(insertSlug)).WithArgs("title", id).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectSetTags(mock, id, nil)
+	mock.ExpectExec(regexp.QuoteMeta(insertRevision)).WithArgs(id, "some-request-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := New(db)
+	response, err := repo.Create(ctx, articles.ArticleCreateUpdate{Title: "title", Body: "body"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, response)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleRepoGetRevisions(t *testing.T) {
+	columns := []string{"article_id", "revision", "title", "body", "request_id", "created_at"}
+	id := uuid.New().String()
+	now := time.Now()
+
+	tests := map[string]struct {
+		unpublished      bool
+		exists           bool
+		expectQueryRows  *sqlmock.Rows
+		expectQueryError error
+		expect           []articles.Revision
+		err              error
+	}{
+		"Happy path": {
+			exists: true,
+			expectQueryRows: sqlmock.NewRows(columns).
+				AddRow(id, 1, "title", "body", "req-1", now).
+				AddRow(id, 2, "title", "new body", "", now),
+			expect: []articles.Revision{
+				{ArticleID: id, Revision: 1, Title: "title", Body: "body", RequestID: "req-1", CreatedAt: now},
+				{ArticleID: id, Revision: 2, Title: "title", Body: "new body", CreatedAt: now},
+			},
+		},
+		"Missing or disabled": {
+			exists: false,
+			expect: []articles.Revision{},
+			err:    articles.ErrArticleNotFound,
+		},
+		"Draft by default": {
+			unpublished: false,
+			exists:      false,
+			expect:      []articles.Revision{},
+			err:         articles.ErrArticleNotFound,
+		},
+		"Unpublished included": {
+			unpublished:     true,
+			exists:          true,
+			expectQueryRows: sqlmock.NewRows(columns).AddRow(id, 1, "draft", "body", "req-1", now),
+			expect:          []articles.Revision{{ArticleID: id, Revision: 1, Title: "draft", Body: "body", RequestID: "req-1", CreatedAt: now}},
+		},
+		"Query error": {
+			exists:           true,
+			expectQueryError: errors.New("some-db-error"),
+			expect:           []articles.Revision{},
+			err:              articles.ErrArticleQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectQuery(regexp.QuoteMeta(visibleArticleExists)).WithArgs(id, test.unpublished).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(test.exists))
+			if test.exists {
+				q := mock.ExpectQuery(regexp.QuoteMeta(selectRevisions)).WithArgs(id, 25, 0).WillReturnError(test.expectQueryError)
+				if test.expectQueryRows != nil {
+					q.WillReturnRows(test.expectQueryRows)
+				}
+			}
+
+			repo := New(db)
+			response, err := repo.GetRevisions(context.Background(), id, test.unpublished, 25, 0)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expect, response)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestArticleRepoGetRevision(t *testing.T) {
+	columns := []string{"article_id", "revision", "title", "body", "request_id", "created_at"}
+	id := uuid.New().String()
+	now := time.Now()
+
+	tests := map[string]struct {
+		exists          bool
+		expectQueryArgs []driver.Value
+		expectQueryRows *sqlmock.Rows
+		expect          articles.Revision
+		err             error
+	}{
+		"Happy path": {
+			exists:          true,
+			expectQueryRows: sqlmock.NewRows(columns).AddRow(id, 3, "title", "body", "req-3", now),
+			expect:          articles.Revision{ArticleID: id, Revision: 3, Title: "title", Body: "body", RequestID: "req-3", CreatedAt: now},
+		},
+		"Missing revision": {
+			exists:          true,
+			expectQueryRows: sqlmock.NewRows(columns),
+			err:             articles.ErrRevisionNotFound,
+		},
+		"Missing, disabled or unpublished article": {
+			exists: false,
+			err:    articles.ErrArticleNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectQuery(regexp.QuoteMeta(visibleArticleExists)).WithArgs(id, false).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(test.exists))
+			if test.exists {
+				mock.ExpectQuery(regexp.QuoteMeta(selectRevision)).WithArgs(id, 3).WillReturnRows(test.expectQueryRows)
+			}
+
+			repo := New(db)
+			response, err := repo.GetRevision(context.Background(), id, false, 3)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expect, response)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
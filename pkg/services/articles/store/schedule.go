@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+// Due articles are claimed with FOR UPDATE SKIP LOCKED, so schedulers running side by side
+// on several replicas each move a different batch and never wait on one another.
+const (
+	publishDueArticles = `UPDATE articles SET status = 'published', updated_at = now(), version = version + 1
+WHERE id IN (SELECT id FROM articles WHERE status = 'in_review' AND disabled_at IS NULL AND publish_at <= now()
+ORDER BY publish_at LIMIT $1 FOR UPDATE SKIP LOCKED)`
+	expireDueArticles = `UPDATE articles SET status = 'archived', updated_at = now(), version = version + 1
+WHERE id IN (SELECT id FROM articles WHERE status = 'published' AND disabled_at IS NULL AND expire_at <= now()
+ORDER BY expire_at LIMIT $1 FOR UPDATE SKIP LOCKED)`
+)
+
+// PublishDue publishes up to limit articles in review whose publishAt has passed and returns how many it published
+func (r *articleRepo) PublishDue(ctx context.Context, limit int) (int, error) {
+	return r.moveDue(ctx, "publish", publishDueArticles, limit)
+}
+
+// ExpireDue archives up to limit published articles whose expireAt has passed and returns how many it archived
+func (r *articleRepo) ExpireDue(ctx context.Context, limit int) (int, error) {
+	return r.moveDue(ctx, "expire", expireDueArticles, limit)
+}
+
+func (r *articleRepo) moveDue(ctx context.Context, action, query string, limit int) (int, error) {
+	res, err := r.DB.ExecContext(ctx, query, limit)
+	if err != nil {
+		log.Error(ctx, "unable to %s due articles: %s", action, err.Error())
+		return 0, articles.ErrArticleUpdate
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Error(ctx, "unable to %s due articles: %s", action, err.Error())
+		return 0, articles.ErrArticleUpdate
+	}
+	return int(n), nil
+}
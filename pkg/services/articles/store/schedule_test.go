@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestArticleRepoMoveDue(t *testing.T) {
+	tests := map[string]struct {
+		expectQuery     string
+		move            func(r articles.Repo) (int, error)
+		expectExecError error
+		expect          int
+		err             error
+	}{
+		"Publish": {
+			expectQuery: publishDueArticles,
+			move:        func(r articles.Repo) (int, error) { return r.PublishDue(context.Background(), 100) },
+			expect:      4,
+		},
+		"Expire": {
+			expectQuery: expireDueArticles,
+			move:        func(r articles.Repo) (int, error) { return r.ExpireDue(context.Background(), 100) },
+			expect:      4,
+		},
+		"Update error": {
+			expectQuery:     publishDueArticles,
+			move:            func(r articles.Repo) (int, error) { return r.PublishDue(context.Background(), 100) },
+			expectExecError: errors.New("some-db-error"),
+			expect:          0,
+			err:             articles.ErrArticleUpdate,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(test.expectQuery)).WithArgs(100).WillReturnError(test.expectExecError).WillReturnResult(sqlmock.NewResult(0, 4))
+
+			n, err := test.move(New(db))
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expect, n)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
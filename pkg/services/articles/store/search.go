@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+const (
+	setSimilarityThreshold     = `SELECT set_config('pg_trgm.similarity_threshold', $1, true)`
+	setWordSimilarityThreshold = `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`
+
+	// searchArticles ranks the hits and narrows them down to the page before anything else is read, so the
+	// tags and snippets (which go through the whole title and body of an article) are only made for that page.
+	// The page itself may be ranked the other way around (see rankBefore), the hits are always returned best first.
+	searchArticles = `SELECT ` + articleColumns + `, score%s FROM (SELECT articles.*, %s AS score FROM %s%s ` +
+		`ORDER BY %s LIMIT %s OFFSET %s) AS articles ORDER BY score DESC, id`
+
+	// rankAfter orders the hits best match first; rankBefore reads the page before a cursor, closest hit first
+	rankAfter  = "score DESC, id"
+	rankBefore = "score, id DESC"
+
+	// fullTextSnippets highlight the keywords of the query in the title and in fragments of the body
+	fullTextSnippets = `, ts_headline('english', coalesce(title, ''), websearch_to_tsquery('english', %[1]s), 'HighlightAll=true'), ` +
+		`ts_headline('english', coalesce(body, ''), websearch_to_tsquery('english', %[1]s), 'MaxFragments=3, MaxWords=25, MinWords=10')`
+)
+
+// Search ranks articles against the query according to the mode of the search. Pages start either at the
+// offset or, when a cursor is given, right after the hit the cursor points to (or end right before it).
+func (r *articleRepo) Search(ctx context.Context, p articles.SearchParams) ([]articles.Article, error) {
+	hits := make([]articles.Article, 0)
+
+	tx, err := r.beginSearch(ctx, p)
+	if err != nil {
+		return hits, err
+	}
+	defer tx.Rollback()
+
+	b, from, score := search(p)
+	var snippets string
+	if p.Mode == articles.SearchFullText {
+		snippets = fmt.Sprintf(fullTextSnippets, "$1")
+	}
+
+	offset, rank := p.Offset, rankAfter
+	if p.Cursor != nil {
+		b.afterScore(score, *p.Cursor)
+		offset = 0
+		if p.Cursor.Before {
+			rank = rankBefore
+		}
+	}
+
+	query := fmt.Sprintf(searchArticles, snippets, score, from, b.conditions(), rank, b.arg(p.Limit), b.arg(offset))
+	rows, err := tx.Query(query, b.args...)
+	if err != nil {
+		log.Warn(ctx, "unable to search articles: %s", err.Error())
+		return hits, articles.ErrArticleQuery
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ar := articles.Article{Score: new(float64)}
+		dest := append(articleDest(&ar), ar.Score)
+		if snippets != "" {
+			ar.Snippet = &articles.Snippet{}
+			dest = append(dest, &ar.Snippet.Title, &ar.Snippet.Body)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			log.Error(ctx, "unable to scan db rows: %s", err.Error())
+			return hits, articles.ErrArticleQuery
+		}
+
+		hits = append(hits, ar)
+	}
+
+	return hits, nil
+}
+
+// CountSearch counts the hits of the search, ignoring its paging, the way p.Total asks for (see count)
+func (r *articleRepo) CountSearch(ctx context.Context, p articles.SearchParams) (total int64, estimated bool, err error) {
+	tx, err := r.beginSearch(ctx, p)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	b, from, _ := search(p)
+	return count(ctx, tx, p.Total, from+b.conditions(), b.args)
+}
+
+// beginSearch starts the read only transaction a search runs in. Fuzzy searches use the % and <% operators
+// so the trigram indexes apply, which means the threshold has to be set on the connection; scoping it to
+// the transaction keeps it from leaking into the pool.
+func (r *articleRepo) beginSearch(ctx context.Context, p articles.SearchParams) (*sql.Tx, error) {
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		log.Error(ctx, "unable to begin search transaction: %s", err.Error())
+		return nil, articles.ErrArticleQuery
+	}
+	if p.Mode == articles.SearchFullText {
+		return tx, nil
+	}
+
+	threshold := strconv.FormatFloat(p.Threshold, 'f', -1, 64)
+	for _, q := range []string{setSimilarityThreshold, setWordSimilarityThreshold} {
+		if _, err := tx.Exec(q, threshold); err != nil {
+			log.Error(ctx, "unable to set similarity threshold: %s", err.Error())
+			tx.Rollback()
+			return nil, articles.ErrArticleQuery
+		}
+	}
+	return tx, nil
+}
+
+// search starts the query of a search with the table articles are searched in and the conditions which hits
+// satisfy, leaving out the paging, along with the score hits are ranked by. Full-text searches match the
+// keywords of the query against the generated search vector and rank the hits with ts_rank; fuzzy searches
+// rank them by the trigram similarity of their title (and optionally body) to the query. Only published
+// articles are ever found.
+func search(p articles.SearchParams) (b *builder, from, score string) {
+	b = &builder{}
+	q := b.arg(p.Query)
+	b.and("disabled_at IS NULL")
+	b.and("status = 'published'")
+
+	switch {
+	case p.Mode == articles.SearchFullText:
+		from = fmt.Sprintf("articles, websearch_to_tsquery('english', %s) query", q)
+		score = "ts_rank(search, query)"
+		b.and("search @@ query")
+	case p.IncludeBody:
+		from = "articles"
+		score = fmt.Sprintf("GREATEST(similarity(title, %[1]s), word_similarity(%[1]s, body))", q)
+		b.and(fmt.Sprintf("(title %% %[1]s OR %[1]s <%% body)", q))
+	default:
+		from = "articles"
+		score = fmt.Sprintf("similarity(title, %s)", q)
+		b.and(fmt.Sprintf("title %% %s", q))
+	}
+
+	// scores are real numbers, which are compared as doubles so the score of a cursor survives the round trip
+	return b, from, score + "::float8"
+}
+
+// afterScore adds the keyset condition which skips every hit up to and including the cursor, hits being
+// ordered by their score, best match first, and then by id. For a cursor pointing Before, every hit from
+// the cursor on is skipped instead.
+func (b *builder) afterScore(score string, c articles.Cursor) {
+	worse, later := "<", ">"
+	if c.Before {
+		worse, later = ">", "<"
+	}
+	s := b.arg(c.Values[0])
+	b.and(fmt.Sprintf("(%[1]s %[4]s %[2]s OR (%[1]s = %[2]s AND id %[5]s %[3]s))", score, s, b.arg(c.ID), worse, later))
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestArticleRepoSearch(t *testing.T) {
+	columns := []string{"id", "slug", "title", "body", "created_at", "updated_at", "disabled_at", "version", "author_id", "status", "publish_at", "expire_at", "tags", "score"}
+	id := uuid.New().String()
+	now := time.Now()
+	score := 0.5
+	hit := articles.Article{ID: id, Slug: "title", Title: "title", Body: "body", CreatedAt: now, UpdatedAt: now, Version: 1, Status: articles.StatusPublished, Tags: []string{"go"}, Score: &score}
+
+	tests := map[string]struct {
+		input            articles.SearchParams
+		expectQuery      string
+		expectArgs       []driver.Value
+		expectQueryError error
+		expect           []articles.Article
+		err              error
+	}{
+		"Titles": {
+			input: articles.SearchParams{Query: "titel", Threshold: 0.4, Limit: 25},
+			expectQuery: `SELECT ` + articleColumns + `, score FROM (SELECT articles.*, similarity(title, $1)::float8 AS score FROM articles ` +
+				`WHERE disabled_at IS NULL AND status = 'published' AND title % $1 ORDER BY score DESC, id LIMIT $2 OFFSET $3) AS articles ORDER BY score DESC, id`,
+			expectArgs: []driver.Value{"titel", 25, 0},
+			expect:     []articles.Article{hit},
+		},
+		"Titles and bodies": {
+			input: articles.SearchParams{Query: "titel", IncludeBody: true, Threshold: 0.4, Limit: 25, Offset: 50},
+			expectQuery: `SELECT ` + articleColumns + `, score FROM (SELECT articles.*, GREATEST(similarity(title, $1), word_similarity($1, body))::float8 AS score FROM articles ` +
+				`WHERE disabled_at IS NULL AND status = 'published' AND (title % $1 OR $1 <% body) ORDER BY score DESC, id LIMIT $2 OFFSET $3) AS articles ORDER BY score DESC, id`,
+			expectArgs: []driver.Value{"titel", 25, 50},
+			expect:     []articles.Article{hit},
+		},
+		"After a cursor": {
+			input: articles.SearchParams{Query: "titel", Threshold: 0.4, Limit: 25, Offset: 50, Cursor: &articles.Cursor{Sort: "rank", Values: []string{"0.75"}, ID: id}},
+			expectQuery: `SELECT ` + articleColumns + `, score FROM (SELECT articles.*, similarity(title, $1)::float8 AS score FROM articles ` +
+				`WHERE disabled_at IS NULL AND status = 'published' AND title % $1 ` +
+				`AND (similarity(title, $1)::float8 < $2 OR (similarity(title, $1)::float8 = $2 AND id > $3)) ` +
+				`ORDER BY score DESC, id LIMIT $4 OFFSET $5) AS articles ORDER BY score DESC, id`,
+			expectArgs: []driver.Value{"titel", "0.75", id, 25, 0},
+			expect:     []articles.Article{hit},
+		},
+		"Before a cursor": {
+			input: articles.SearchParams{Query: "titel", Threshold: 0.4, Limit: 25, Cursor: &articles.Cursor{Sort: "rank", Values: []string{"0.75"}, ID: id, Before: true}},
+			expectQuery: `SELECT ` + articleColumns + `, score FROM (SELECT articles.*, similarity(title, $1)::float8 AS score FROM articles ` +
+				`WHERE disabled_at IS NULL AND status = 'published' AND title % $1 ` +
+				`AND (similarity(title, $1)::float8 > $2 OR (similarity(title, $1)::float8 = $2 AND id < $3)) ` +
+				`ORDER BY score, id DESC LIMIT $4 OFFSET $5) AS articles ORDER BY score DESC, id`,
+			expectArgs: []driver.Value{"titel", "0.75", id, 25, 0},
+			expect:     []articles.Article{hit},
+		},
+		"Query error": {
+			input: articles.SearchParams{Query: "titel", Threshold: 0.4, Limit: 25},
+			expectQuery: `SELECT ` + articleColumns + `, score FROM (SELECT articles.*, similarity(title, $1)::float8 AS score FROM articles ` +
+				`WHERE disabled_at IS NULL AND status = 'published' AND title % $1 ORDER BY score DESC, id LIMIT $2 OFFSET $3) AS articles ORDER BY score DESC, id`,
+			expectArgs:       []driver.Value{"titel", 25, 0},
+			expectQueryError: errors.New("some-db-error"),
+			expect:           []articles.Article{},
+			err:              articles.ErrArticleQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(setSimilarityThreshold)).WithArgs("0.4").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(setWordSimilarityThreshold)).WithArgs("0.4").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(regexp.QuoteMeta(test.expectQuery)).
+				WithArgs(test.expectArgs...).
+				WillReturnError(test.expectQueryError).
+				WillReturnRows(sqlmock.NewRows(columns).AddRow([]driver.Value{id, "title", "title", "body", now, now, nil, 1, nil, "published", nil, nil, "{go}", 0.5}...))
+			mock.ExpectRollback()
+
+			repo := New(db)
+			response, err := repo.Search(context.Background(), test.input)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expect, response)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestArticleRepoSearchFullText(t *testing.T) {
+	columns := []string{"id", "slug", "title", "body", "created_at", "updated_at", "disabled_at", "version", "author_id", "status", "publish_at", "expire_at", "tags", "score", "ts_headline", "ts_headline"}
+	id := uuid.New().String()
+	now := time.Now()
+	score := 0.25
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT `+articleColumns+`, score, `+
+		`ts_headline('english', coalesce(title, ''), websearch_to_tsquery('english', $1), 'HighlightAll=true'), `+
+		`ts_headline('english', coalesce(body, ''), websearch_to_tsquery('english', $1), 'MaxFragments=3, MaxWords=25, MinWords=10') `+
+		`FROM (SELECT articles.*, ts_rank(search, query)::float8 AS score FROM articles, websearch_to_tsquery('english', $1) query `+
+		`WHERE disabled_at IS NULL AND status = 'published' AND search @@ query ORDER BY score DESC, id LIMIT $2 OFFSET $3) AS articles ORDER BY score DESC, id`)).
+		WithArgs("go database", 10, 20).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(id, "title", "title", "body", now, now, nil, 1, nil, "published", nil, nil, "{go}", 0.25, "<b>title</b>", "<b>body</b>"))
+	mock.ExpectRollback()
+
+	repo := New(db)
+	response, err := repo.Search(context.Background(), articles.SearchParams{Query: "go database", Mode: articles.SearchFullText, Limit: 10, Offset: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []articles.Article{{
+		ID: id, Slug: "title", Title: "title", Body: "body", CreatedAt: now, UpdatedAt: now, Version: 1, Status: articles.StatusPublished, Tags: []string{"go"},
+		Score:   &score,
+		Snippet: &articles.Snippet{Title: "<b>title</b>", Body: "<b>body</b>"},
+	}}, response)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleRepoCountSearch(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM articles, websearch_to_tsquery('english', $1) query ` +
+		`WHERE disabled_at IS NULL AND status = 'published' AND search @@ query`)).
+		WithArgs("go database").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+	mock.ExpectRollback()
+
+	repo := New(db)
+	total, estimated, err := repo.CountSearch(context.Background(), articles.SearchParams{
+		Query:  "go database",
+		Mode:   articles.SearchFullText,
+		Limit:  10,
+		Cursor: &articles.Cursor{Sort: "rank", Values: []string{"0.5"}, ID: "id"},
+		Total:  articles.TotalExact,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), total)
+	assert.False(t, estimated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+// Every slug an article ever had is kept in article_slugs, so earlier slugs keep resolving to it and are
+// never handed to another article. Slugs sharing a base are claimed under an advisory lock on that base,
+// which serializes concurrent claims without failing any of them. A numbered slug is the base of another
+// title as well ("Foo" may claim foo-2 while "Foo 2" claims it as its base), so it is locked on its own
+// before it is claimed, and checked again since the other title may have claimed it in the meantime.
+const (
+	lockSlug           = `SELECT pg_advisory_xact_lock(hashtext($1))`
+	selectSlugsForBase = `SELECT slug, article_id FROM article_slugs WHERE slug = $1 OR slug LIKE $2`
+	selectSlugTaken    = `SELECT EXISTS(SELECT 1 FROM article_slugs WHERE slug = $1)`
+	insertSlug         = `INSERT INTO article_slugs (slug, article_id, created_at) VALUES ($1, $2, now())`
+	selectArticleSlug  = `SELECT slug FROM articles WHERE id = $1`
+	updateArticleSlug  = `UPDATE articles SET slug = $1 WHERE id = $2 AND slug <> $1`
+	selectSlugArticle  = `SELECT articles.id, articles.slug FROM article_slugs JOIN articles ON articles.id = article_id WHERE article_slugs.slug = $1`
+)
+
+// reservedSlugs are the paths next to /articles/:id which would shadow an article with that slug
+var reservedSlugs = map[string]bool{
+	"trash":  true,
+	"search": true,
+	"export": true,
+}
+
+// ResolveSlug looks up the article which has or had the slug and returns its id and current slug
+func (r *articleRepo) ResolveSlug(ctx context.Context, slug string) (string, string, error) {
+	var id, canonical string
+	if err := r.DB.QueryRow(selectSlugArticle, slug).Scan(&id, &canonical); err != nil {
+		log.Info(ctx, "select article by slug error: %s", err.Error())
+		return "", "", articles.ErrArticleNotFound
+	}
+	return id, canonical, nil
+}
+
+// pickSlug locks the base of the slug for title and picks the slug the article with the given id (empty
+// for a new article) should have. The current slug is kept while it still matches the title, and slugs
+// the article had before are reused. claim is set when the slug still has to be added to article_slugs.
+func pickSlug(tx *sql.Tx, id, current, title string) (slug string, claim bool, err error) {
+	base := articles.Slugify(title)
+	if current != "" && slugHasBase(current, base) {
+		return current, false, nil
+	}
+
+	if _, err := tx.Exec(lockSlug, base); err != nil {
+		return "", false, err
+	}
+
+	rows, err := tx.Query(selectSlugsForBase, base, base+"-%")
+	if err != nil {
+		return "", false, err
+	}
+	defer rows.Close()
+
+	owners := make(map[string]string)
+	for rows.Next() {
+		var s, owner string
+		if err := rows.Scan(&s, &owner); err != nil {
+			return "", false, err
+		}
+		owners[s] = owner
+	}
+	if err := rows.Err(); err != nil {
+		return "", false, err
+	}
+
+	for n := 1; ; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+		if slugReserved(candidate) {
+			continue
+		}
+
+		if owner, taken := owners[candidate]; taken {
+			if owner == id {
+				return candidate, false, nil
+			}
+			continue
+		}
+		if candidate != base {
+			taken, err := lockFreeSlug(tx, candidate)
+			if err != nil {
+				return "", false, err
+			}
+			if taken {
+				continue
+			}
+		}
+		return candidate, true, nil
+	}
+}
+
+// lockFreeSlug locks a numbered slug which was free when the slugs of its base were read and reports whether it
+// has been taken since
+func lockFreeSlug(tx *sql.Tx, slug string) (taken bool, err error) {
+	if _, err := tx.Exec(lockSlug, slug); err != nil {
+		return false, err
+	}
+	err = tx.QueryRow(selectSlugTaken, slug).Scan(&taken)
+	return taken, err
+}
+
+// slugHasBase reports whether the slug is the base itself or the base with a numeric suffix
+func slugHasBase(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	suffix := strings.TrimPrefix(slug, base+"-")
+	if suffix == slug {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// slugReserved reports whether the slug cannot be used since it would be taken for a route or an id
+func slugReserved(slug string) bool {
+	if reservedSlugs[slug] {
+		return true
+	}
+	_, err := uuid.Parse(slug)
+	return err == nil
+}
+
+// setSlug gives the article the slug matching its new title, unless its current slug already does
+func setSlug(tx *sql.Tx, id, title string) error {
+	var current string
+	if err := tx.QueryRow(selectArticleSlug, id).Scan(&current); err != nil {
+		return err
+	}
+
+	slug, claim, err := pickSlug(tx, id, current, title)
+	if err != nil {
+		return err
+	}
+	if claim {
+		if _, err := tx.Exec(insertSlug, slug, id); err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(updateArticleSlug, slug, id)
+	return err
+}
@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestPickSlug(t *testing.T) {
+	id := uuid.New().String()
+	other := uuid.New().String()
+
+	tests := map[string]struct {
+		id        string
+		current   string
+		title     string
+		owned     map[string]string
+		locked    []string
+		meanwhile map[string]bool
+		expect    string
+		claim     bool
+		expectErr bool
+		queryErr  error
+	}{
+		"Free": {
+			title:  "Some Title",
+			expect: "some-title",
+			claim:  true,
+		},
+		"Taken": {
+			title:  "Some Title",
+			owned:  map[string]string{"some-title": other, "some-title-2": other},
+			locked: []string{"some-title-3"},
+			expect: "some-title-3",
+			claim:  true,
+		},
+		"Taken meanwhile": {
+			title:     "Some Title",
+			owned:     map[string]string{"some-title": other},
+			locked:    []string{"some-title-2", "some-title-3"},
+			meanwhile: map[string]bool{"some-title-2": true},
+			expect:    "some-title-3",
+			claim:     true,
+		},
+		"Keeps current": {
+			id:      id,
+			current: "some-title-2",
+			title:   "some title",
+			expect:  "some-title-2",
+		},
+		"Reuses earlier slug": {
+			id:      id,
+			current: "other-title",
+			title:   "Some Title",
+			owned:   map[string]string{"some-title": other, "some-title-2": id},
+			expect:  "some-title-2",
+		},
+		"Reserved": {
+			title:  "Search",
+			locked: []string{"search-2"},
+			expect: "search-2",
+			claim:  true,
+		},
+		"Query error": {
+			title:     "Some Title",
+			queryErr:  errors.New("some-db-error"),
+			expectErr: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			base := articles.Slugify(test.title)
+			mock.ExpectBegin()
+			if test.current == "" || !slugHasBase(test.current, base) {
+				rows := sqlmock.NewRows([]string{"slug", "article_id"})
+				for slug, owner := range test.owned {
+					rows.AddRow(slug, owner)
+				}
+				mock.ExpectExec(regexp.QuoteMeta(lockSlug)).WithArgs(base).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta(selectSlugsForBase)).WithArgs(base, base+"-%").WillReturnError(test.queryErr).WillReturnRows(rows)
+			}
+			for _, slug := range test.locked {
+				mock.ExpectExec(regexp.QuoteMeta(lockSlug)).WithArgs(slug).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta(selectSlugTaken)).WithArgs(slug).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(test.meanwhile[slug]))
+			}
+
+			tx, err := db.Begin()
+			require.NoError(t, err)
+
+			slug, claim, err := pickSlug(tx, test.id, test.current, test.title)
+
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expect, slug)
+			assert.Equal(t, test.claim, claim)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSlugHasBase(t *testing.T) {
+	assert.True(t, slugHasBase("title", "title"))
+	assert.True(t, slugHasBase("title-12", "title"))
+	assert.False(t, slugHasBase("title-page", "title"))
+	assert.False(t, slugHasBase("titles", "title"))
+}
+
+func TestArticleRepoResolveSlug(t *testing.T) {
+	id := uuid.New().String()
+
+	tests := map[string]struct {
+		rows      *sqlmock.Rows
+		expectID  string
+		expectErr error
+	}{
+		"Happy path": {
+			rows:     sqlmock.NewRows([]string{"id", "slug"}).AddRow(id, "new-title"),
+			expectID: id,
+		},
+		"Not found": {
+			rows:      sqlmock.NewRows([]string{"id", "slug"}),
+			expectErr: articles.ErrArticleNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectQuery(regexp.QuoteMeta(selectSlugArticle)).WithArgs("old-title").WillReturnRows(test.rows)
+
+			repo := New(db)
+			resolved, canonical, err := repo.ResolveSlug(context.Background(), "old-title")
+
+			assert.Equal(t, test.expectErr, err)
+			assert.Equal(t, test.expectID, resolved)
+			if test.expectErr == nil {
+				assert.Equal(t, "new-title", canonical)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+const (
+	setArticleStatus    = `UPDATE articles SET status = $1, updated_at = now(), version = version + 1 WHERE id = $2 AND disabled_at IS NULL AND status = $3 AND ($4 = 0 OR version = $4)`
+	selectArticleStatus = `SELECT status, version FROM articles WHERE id = $1 AND disabled_at IS NULL`
+)
+
+// SetStatus makes the transition on the requested version of the article, provided the article is in
+// the status the transition starts from. A version of 0 skips the version check.
+func (r *articleRepo) SetStatus(ctx context.Context, id string, t articles.Transition, version int) error {
+	res, err := r.DB.Exec(setArticleStatus, t.To, id, t.From, version)
+	if err != nil {
+		log.Error(ctx, "unable to %s article (%s): %s", t.Name, id, err.Error())
+		return articles.ErrArticleUpdate
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Error(ctx, "unable to %s article (%s): %s", t.Name, id, err.Error())
+		return articles.ErrArticleUpdate
+	}
+	if n == 0 {
+		return r.transitionErr(ctx, id, t)
+	}
+
+	log.Info(ctx, "moved article with id=%s from %s to %s", id, t.From, t.To)
+	return nil
+}
+
+// transitionErr determines why a transition did not touch any rows: either the article does
+// not exist (or is disabled), it is in another status or the version did not match.
+func (r *articleRepo) transitionErr(ctx context.Context, id string, t articles.Transition) error {
+	var status articles.Status
+	var current int
+	if err := r.DB.QueryRow(selectArticleStatus, id).Scan(&status, &current); err != nil {
+		if err != sql.ErrNoRows {
+			log.Info(ctx, "select article status error: %s", err.Error())
+		}
+		return articles.ErrArticleNotFound
+	}
+	if status != t.From {
+		log.Info(ctx, "cannot %s article (%s) which is %s", t.Name, id, status)
+		return articles.ErrInvalidTransition
+	}
+	return articles.ErrArticleVersionMismatch
+}
+
+// withStatuses narrows the listing down to articles in one of the statuses, if any are given
+func (b *builder) withStatuses(statuses []articles.Status) {
+	if len(statuses) == 0 {
+		return
+	}
+
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		names[i] = string(s)
+	}
+	b.and(fmt.Sprintf("status = ANY(%s)", b.arg(pq.Array(names))))
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestArticleRepoSetStatus(t *testing.T) {
+	id := uuid.New().String()
+	publish := articles.Transitions["publish"]
+
+	tests := map[string]struct {
+		version          int
+		expectExecResult driver.Result
+		expectExecError  error
+		expectStatusRows *sqlmock.Rows
+		err              error
+	}{
+		"Happy path": {
+			version:          3,
+			expectExecResult: sqlmock.NewResult(0, 1),
+		},
+		"Not found": {
+			expectExecResult: sqlmock.NewResult(0, 0),
+			expectStatusRows: sqlmock.NewRows([]string{"status", "version"}),
+			err:              articles.ErrArticleNotFound,
+		},
+		"Wrong status": {
+			expectExecResult: sqlmock.NewResult(0, 0),
+			expectStatusRows: sqlmock.NewRows([]string{"status", "version"}).AddRow("draft", 3),
+			err:              articles.ErrInvalidTransition,
+		},
+		"Version mismatch": {
+			version:          2,
+			expectExecResult: sqlmock.NewResult(0, 0),
+			expectStatusRows: sqlmock.NewRows([]string{"status", "version"}).AddRow("in_review", 3),
+			err:              articles.ErrArticleVersionMismatch,
+		},
+		"Update error": {
+			expectExecError: errors.New("some-db-error"),
+			err:             articles.ErrArticleUpdate,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(setArticleStatus)).
+				WithArgs("published", id, "in_review", test.version).
+				WillReturnError(test.expectExecError).
+				WillReturnResult(test.expectExecResult)
+			if test.expectStatusRows != nil {
+				mock.ExpectQuery(regexp.QuoteMeta(selectArticleStatus)).WithArgs(id).WillReturnRows(test.expectStatusRows)
+			}
+
+			repo := New(db)
+			err := repo.SetStatus(context.Background(), id, publish, test.version)
+
+			assert.Equal(t, test.err, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
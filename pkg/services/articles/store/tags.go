@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+const (
+	deleteArticleTags = `DELETE FROM article_tags WHERE article_id = $1`
+	insertTags        = `INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`
+	insertArticleTags = `INSERT INTO article_tags (article_id, tag_id) SELECT $1, id FROM tags WHERE name = ANY($2)`
+
+	// selectTags only counts (and thereby only lists) tags of live, published articles, so the tags of
+	// drafts do not give them away
+	selectTags = `SELECT name, COUNT(*) AS count FROM tags
+JOIN article_tags ON tag_id = tags.id
+JOIN articles ON articles.id = article_id
+WHERE disabled_at IS NULL AND status = 'published'
+GROUP BY name ORDER BY count DESC, name LIMIT $1 OFFSET $2`
+
+	// tagsAny and tagsAll are the conditions for articles carrying any or all
+	// of the tags given by the first placeholder
+	tagsAny = `EXISTS (SELECT 1 FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id AND name = ANY(%s))`
+	tagsAll = `(SELECT COUNT(*) FROM article_tags JOIN tags ON tags.id = tag_id WHERE article_id = articles.id AND name = ANY(%s)) = %s`
+)
+
+// setTags replaces the tags of an article, creating tags which do not exist yet
+func setTags(q querier, id string, tags []string) error {
+	if _, err := q.Exec(deleteArticleTags, id); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if _, err := q.Exec(insertTags, pq.Array(tags)); err != nil {
+		return err
+	}
+	_, err := q.Exec(insertArticleTags, id, pq.Array(tags))
+	return err
+}
+
+// GetTags retrieves the tags in use along with the number of live, published articles carrying them, most used first
+func (r *articleRepo) GetTags(ctx context.Context, limit, offset int) ([]articles.Tag, error) {
+	tags := make([]articles.Tag, 0)
+
+	rows, err := r.DB.Query(selectTags, limit, offset)
+	if err != nil {
+		log.Warn(ctx, "unable to query tags: %s", err.Error())
+		return tags, articles.ErrArticleQuery
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t articles.Tag
+		if err := rows.Scan(&t.Name, &t.Count); err != nil {
+			log.Error(ctx, "unable to scan db rows: %s", err.Error())
+			return tags, articles.ErrArticleQuery
+		}
+
+		tags = append(tags, t)
+	}
+
+	return tags, nil
+}
+
+// withTags adds the condition that listed articles carry any or all of the tags.
+// The tags are expected to be free of duplicates.
+func (b *builder) withTags(tags []string, match articles.TagMatch) {
+	if len(tags) == 0 {
+		return
+	}
+
+	names := b.arg(pq.Array(tags))
+	if match == articles.TagMatchAll {
+		b.and(fmt.Sprintf(tagsAll, names, b.arg(len(tags))))
+		return
+	}
+	b.and(fmt.Sprintf(tagsAny, names))
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestArticleRepoGetTags(t *testing.T) {
+	columns := []string{"name", "count"}
+
+	tests := map[string]struct {
+		expectQueryRows  *sqlmock.Rows
+		expectQueryError error
+		expect           []articles.Tag
+		err              error
+	}{
+		"Happy path": {
+			expectQueryRows: sqlmock.NewRows(columns).AddRow("go", 3).AddRow("db", 1),
+			expect:          []articles.Tag{{Name: "go", Count: 3}, {Name: "db", Count: 1}},
+		},
+		"Query error": {
+			expectQueryError: errors.New("some-db-error"),
+			expect:           []articles.Tag{},
+			err:              articles.ErrArticleQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			q := mock.ExpectQuery(regexp.QuoteMeta("WHERE disabled_at IS NULL AND status = 'published'")).WithArgs(25, 0).WillReturnError(test.expectQueryError)
+			if test.expectQueryRows != nil {
+				q.WillReturnRows(test.expectQueryRows)
+			}
+
+			repo := New(db)
+			response, err := repo.GetTags(context.Background(), 25, 0)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expect, response)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+// querier is implemented by both *sql.DB and *sql.Tx, so statements can be shared
+// between standalone use and use within a transaction.
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// inTx runs fn within a transaction which is committed when fn succeeds and rolled back otherwise.
+// Errors returned by fn are passed through as they are, failErr is returned when the transaction
+// itself cannot be started or committed.
+func (r *articleRepo) inTx(ctx context.Context, failErr error, fn func(tx *sql.Tx) error) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error(ctx, "unable to begin transaction: %s", err.Error())
+		return failErr
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(ctx, "unable to commit transaction: %s", err.Error())
+		return failErr
+	}
+	return nil
+}
+
+const (
+	// foreignKeyViolation is the SQLSTATE of a row referencing a row which does not exist
+	foreignKeyViolation = "23503"
+	// checkViolation is the SQLSTATE of a row failing a CHECK constraint
+	checkViolation = "23514"
+)
+
+// isForeignKeyViolation reports whether the statement failed because it referenced a row which does not exist
+func isForeignKeyViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == foreignKeyViolation
+}
+
+// isCheckViolation reports whether the statement failed because the row did not pass a CHECK constraint
+func isCheckViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == checkViolation
+}
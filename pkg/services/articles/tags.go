@@ -0,0 +1,36 @@
+package articles
+
+import (
+	"sort"
+	"strings"
+)
+
+// MaxTagLength is the maximum number of characters of a tag, as also enforced by the binding of ArticleCreateUpdate
+const MaxTagLength = 50
+
+// TagMatch selects whether articles have to carry any or all of the tags they are filtered by
+type TagMatch int
+
+const (
+	// TagMatchAny matches articles carrying at least one of the tags
+	TagMatchAny TagMatch = iota
+	// TagMatchAll matches articles carrying every one of the tags
+	TagMatchAll
+)
+
+// NormalizeTags trims and lower cases the tags, dropping empty ones and duplicates. The result is sorted
+// so it matches the order in which the tags of an article are read back.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		normalized = append(normalized, t)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
@@ -0,0 +1,33 @@
+package articles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := map[string]struct {
+		input  []string
+		expect []string
+	}{
+		"None": {
+			input:  nil,
+			expect: []string{},
+		},
+		"Sorted and lower cased": {
+			input:  []string{"Go", "db", " API "},
+			expect: []string{"api", "db", "go"},
+		},
+		"Duplicates and empty tags dropped": {
+			input:  []string{"go", "GO", "", "  ", "db", "go "},
+			expect: []string{"db", "go"},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, test.expect, NormalizeTags(test.input))
+		})
+	}
+}
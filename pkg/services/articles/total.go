@@ -0,0 +1,15 @@
+package articles
+
+// TotalCount selects how the articles of a whole listing are counted
+type TotalCount int
+
+const (
+	// TotalAuto counts exactly, unless there are so many articles that counting them would be slow
+	TotalAuto TotalCount = iota
+	// TotalExact counts every article of the listing
+	TotalExact
+	// TotalEstimate takes the number of articles the query planner expects the listing to have
+	TotalEstimate
+	// TotalNone leaves the total out
+	TotalNone
+)
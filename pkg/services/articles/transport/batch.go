@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/context"
+	"github.com/kott/go-service-example/pkg/utils/log"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
+)
+
+// maxBatchSize is the largest number of operations accepted in a single batch
+const maxBatchSize = 1000
+
+var batchModes = map[string]bool{
+	"atomic":     true,
+	"bestEffort": false,
+}
+
+// batchResponse holds the article of every applied operation at the index of the operation, and
+// an error for every operation which failed, or was not attempted, with its index in the Field
+type batchResponse struct {
+	Articles []*articles.Article `json:"articles"`
+	errors.AppErrors
+}
+
+// Batch applies an array of create and update operations. In the default atomic mode either all of them are
+// applied or none are, in bestEffort mode every valid operation which succeeds is applied regardless of the others.
+func (h *handler) Batch(c *gin.Context) {
+	var q struct {
+		Mode string `form:"mode,default=atomic"`
+	}
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	atomic, ok := batchModes[q.Mode]
+	if !ok {
+		log.Info(ctx, "invalid batch mode: %s", q.Mode)
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, fmt.Sprintf("unknown batch mode %q", q.Mode), "mode"))
+		return
+	}
+
+	var raw []json.RawMessage
+	if err := c.ShouldBindJSON(&raw); err != nil {
+		log.Info(ctx, "request parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+	if len(raw) == 0 || len(raw) > maxBatchSize {
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, fmt.Sprintf("a batch must hold between 1 and %d operations", maxBatchSize), ""))
+		return
+	}
+
+	// the operations which pass validation are handed to the service, indexes maps them back to the request
+	var ops []articles.BatchOperation
+	var indexes []int
+	failures := make([]*errors.AppError, len(raw))
+	for i, r := range raw {
+		op, appErr := parseBatchOperation(r)
+		if appErr != nil {
+			appErr.Field = batchField(i, appErr.Field)
+			failures[i] = appErr
+			continue
+		}
+		ops = append(ops, op)
+		indexes = append(indexes, i)
+	}
+
+	status := http.StatusOK
+	if len(ops) < len(raw) {
+		status = http.StatusBadRequest
+	}
+
+	response := batchResponse{Articles: make([]*articles.Article, len(raw))}
+	if len(ops) > 0 && (!atomic || status == http.StatusOK) {
+		log.Info(ctx, "applying batch of %d articles, atomic=%t", len(ops), atomic)
+		results, err := h.ArticleService.Batch(ctx, ops, atomic)
+		if err != nil {
+			status, appErr := handleError(err)
+			negotiate.Render(c, status, appErr)
+			return
+		}
+
+		for i, r := range results {
+			index := indexes[i]
+			if r.Err != nil {
+				s, appErr := batchError(index, r.Err)
+				if status == http.StatusOK {
+					status = s
+				}
+				failures[index] = appErr
+				continue
+			}
+			if r.Article.ID != "" {
+				ar := r.Article
+				response.Articles[index] = &ar
+			}
+		}
+	}
+
+	response.Errors = make([]errors.AppError, 0)
+	for _, f := range failures {
+		if f != nil {
+			response.Errors = append(response.Errors, *f)
+		}
+	}
+	if len(response.Errors) > 0 && !atomic {
+		status = http.StatusMultiStatus
+	}
+	negotiate.Render(c, status, response)
+}
+
+// parseBatchOperation decodes and validates a single operation of a batch
+func parseBatchOperation(raw json.RawMessage) (articles.BatchOperation, *errors.AppError) {
+	var op articles.BatchOperation
+	if err := json.Unmarshal(raw, &op); err != nil {
+		return op, &errors.AppError{Code: errors.BadRequest, Description: errors.Descriptions[errors.BadRequest]}
+	}
+	if err := binding.Validator.ValidateStruct(&op); err != nil {
+		return op, &errors.AppError{Code: errors.BadRequest, Description: errors.Descriptions[errors.BadRequest]}
+	}
+
+	switch {
+	case op.Op == articles.BatchUpdate && op.ID == "":
+		return op, &errors.AppError{Code: errors.BadRequest, Description: "id is required to update an article", Field: "id"}
+	case op.Op == articles.BatchCreate && op.ID != "":
+		return op, &errors.AppError{Code: errors.BadRequest, Description: "id cannot be set when creating an article", Field: "id"}
+	case op.Op == articles.BatchCreate && op.Version != 0:
+		return op, &errors.AppError{Code: errors.BadRequest, Description: "version cannot be set when creating an article", Field: "version"}
+	}
+	return op, nil
+}
+
+// batchError translates the error an operation failed with and ties it to the index of the operation
+func batchError(index int, err error) (int, *errors.AppError) {
+	status, e := handleError(err)
+	appErr := *e.(*errors.AppError)
+	if appErr.Field == ifMatchHeader {
+		appErr.Field = "version"
+	}
+	appErr.Field = batchField(index, appErr.Field)
+	return status, &appErr
+}
+
+// batchField encodes the index of an operation, and the field of the operation if there is one, as "[index].field"
+func batchField(index int, field string) string {
+	if field == "" || field == "unknown" {
+		return fmt.Sprintf("[%d]", index)
+	}
+	return fmt.Sprintf("[%d].%s", index, field)
+}
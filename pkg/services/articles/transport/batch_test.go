@@ -0,0 +1,155 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestHandlerBatch(t *testing.T) {
+	id := uuid.New().String()
+	created := articles.Article{ID: uuid.New().String(), Title: "created", Version: 1}
+	updated := articles.Article{ID: id, Title: "updated", Version: 3}
+	body := `[
+		{"op": "create", "article": {"title": "created", "body": "body"}},
+		{"op": "update", "id": "` + id + `", "version": 2, "article": {"title": "updated", "body": "body"}}
+	]`
+
+	tests := map[string]struct {
+		mockService *mockService
+		uri         string
+		body        string
+		called      bool
+		response    batchResponse
+		status      int
+	}{
+		"Happy path": {
+			mockService: &mockService{BatchResult: []articles.BatchResult{{Article: created}, {Article: updated}}},
+			uri:         "/articles/batch",
+			body:        body,
+			called:      true,
+			response:    batchResponse{Articles: []*articles.Article{&created, &updated}, AppErrors: errors.AppErrors{Errors: []errors.AppError{}}},
+			status:      http.StatusOK,
+		},
+		"Atomic failure": {
+			mockService: &mockService{BatchResult: []articles.BatchResult{{}, {Err: articles.ErrArticleVersionMismatch}}},
+			uri:         "/articles/batch",
+			body:        body,
+			called:      true,
+			response: batchResponse{Articles: []*articles.Article{nil, nil}, AppErrors: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.PreconditionFailed, Description: articles.ErrArticleVersionMismatch.Error(), Field: "[1].version"},
+			}}},
+			status: http.StatusPreconditionFailed,
+		},
+		"Best effort failure": {
+			mockService: &mockService{BatchResult: []articles.BatchResult{{Article: created}, {Err: articles.ErrArticleNotFound}}},
+			uri:         "/articles/batch?mode=bestEffort",
+			body:        body,
+			called:      true,
+			response: batchResponse{Articles: []*articles.Article{&created, nil}, AppErrors: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.NotFound, Description: articles.ErrArticleNotFound.Error(), Field: "[1].id"},
+			}}},
+			status: http.StatusMultiStatus,
+		},
+		"Invalid operations": {
+			mockService: &mockService{},
+			uri:         "/articles/batch",
+			body:        `[{"op": "update", "article": {"title": "t", "body": "b"}}, {"op": "create", "article": {"title": "t", "body": "b"}}, {"op": "delete"}]`,
+			response: batchResponse{Articles: []*articles.Article{nil, nil, nil}, AppErrors: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "id is required to update an article", Field: "[0].id"},
+				{Code: errors.BadRequest, Description: errors.Descriptions[errors.BadRequest], Field: "[2]"},
+			}}},
+			status: http.StatusBadRequest,
+		},
+		"Invalid operations best effort": {
+			mockService: &mockService{BatchResult: []articles.BatchResult{{Article: created}}},
+			uri:         "/articles/batch?mode=bestEffort",
+			body:        `[{"op": "create", "id": "` + id + `", "article": {"title": "t", "body": "b"}}, {"op": "create", "article": {"title": "created", "body": "b"}}]`,
+			called:      true,
+			response: batchResponse{Articles: []*articles.Article{nil, &created}, AppErrors: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "id cannot be set when creating an article", Field: "[0].id"},
+			}}},
+			status: http.StatusMultiStatus,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodPost, test.uri, strings.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Add("Content-Type", "application/json")
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			var br batchResponse
+			if err := json.Unmarshal(response.Body.Bytes(), &br); err != nil {
+				assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+			}
+			assert.Equal(t, test.response, br)
+			assert.Equal(t, test.called, test.mockService.BatchOps != nil)
+			assert.Equal(t, test.called && !strings.Contains(test.uri, "bestEffort"), test.mockService.BatchAtomic)
+		})
+	}
+}
+
+func TestHandlerBatchRejectsRequest(t *testing.T) {
+	tests := map[string]struct {
+		uri      string
+		body     string
+		response errors.AppError
+	}{
+		"Unknown mode": {
+			uri:      "/articles/batch?mode=sometimes",
+			body:     `[{"op": "create", "article": {"title": "t", "body": "b"}}]`,
+			response: errors.AppError{Code: errors.BadRequest, Description: `unknown batch mode "sometimes"`, Field: "mode"},
+		},
+		"Not an array": {
+			uri:      "/articles/batch",
+			body:     `{"op": "create"}`,
+			response: errors.AppError{Code: errors.BadRequest, Description: errors.Descriptions[errors.BadRequest]},
+		},
+		"Empty": {
+			uri:      "/articles/batch",
+			body:     `[]`,
+			response: errors.AppError{Code: errors.BadRequest, Description: "a batch must hold between 1 and 1000 operations"},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, &mockService{}, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodPost, test.uri, strings.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Add("Content-Type", "application/json")
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, http.StatusBadRequest, response.Code)
+
+			var appErr errors.AppError
+			if err := json.Unmarshal(response.Body.Bytes(), &appErr); err != nil {
+				assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+			}
+			assert.Equal(t, test.response, appErr)
+		})
+	}
+}
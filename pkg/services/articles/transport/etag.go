@@ -0,0 +1,164 @@
+package transport
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+const (
+	etagHeader            = "ETag"
+	lastModifiedHeader    = "Last-Modified"
+	ifMatchHeader         = "If-Match"
+	ifNoneMatchHeader     = "If-None-Match"
+	ifModifiedSinceHeader = "If-Modified-Since"
+)
+
+// etagFormat names the negotiated format, the media type of the representation, in entity tags, e.g. "xml"
+func etagFormat(format string) string {
+	return format[strings.LastIndex(format, "/")+1:]
+}
+
+// etag is the strong entity tag for the current version of an article in the negotiated format, e.g. "3-json".
+// Each format is a representation of its own, so a cache holding the JSON of an article cannot answer a
+// conditional request for its XML.
+func etag(ar articles.Article, format string) string {
+	return fmt.Sprintf(`"%d-%s"`, ar.Version, etagFormat(format))
+}
+
+// listETag is a strong entity tag for a page of articles in the negotiated format. Since the version of an article
+// changes whenever anything about it does, the ids and versions (plus the cursors and total) are enough to identify
+// the page. The tag also covers what Last-Modified cannot: an article dropping out of a page moves an older one
+// in, so the newest update on the page can stay the same while the page changes.
+func listETag(page articles.Articles, format string) string {
+	h := sha1.New()
+	for _, ar := range page.Articles {
+		fmt.Fprintf(h, "%s:%d;", ar.ID, ar.Version)
+	}
+	fmt.Fprintf(h, "%s;%s", page.NextCursor, page.PrevCursor)
+	if page.Total != nil {
+		fmt.Fprintf(h, ";%d", *page.Total)
+	}
+	return fmt.Sprintf(`"%x-%s"`, h.Sum(nil), etagFormat(format))
+}
+
+// lastModified is the most recent update time among the given articles
+func lastModified(al ...articles.Article) time.Time {
+	var t time.Time
+	for _, ar := range al {
+		if ar.UpdatedAt.After(t) {
+			t = ar.UpdatedAt
+		}
+	}
+	return t
+}
+
+// ifMatchVersions reads the article versions listed by an If-Match header (RFC 7232, section 3.1), which is
+// either "*" or a comma separated list of entity tags. A change is made to the article rather than to one of its
+// representations, so the format part of a tag is left aside and "3-json" and "3-xml" both list version 3. Weak
+// or malformed tags can never match and are left out.
+func ifMatchVersions(header string) (versions []int, wildcard bool) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return nil, true
+	}
+
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if len(t) < 2 || t[0] != '"' || t[len(t)-1] != '"' {
+			continue
+		}
+		t = t[1 : len(t)-1]
+		if i := strings.IndexByte(t, '-'); i >= 0 {
+			t = t[:i]
+		}
+		version, err := strconv.Atoi(t)
+		if err != nil || version < 1 {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, false
+}
+
+// ifMatchVersion resolves the If-Match header of a change to the article into the version the change is made
+// against, where 0 means the change is not conditional. A single listed version is passed on as it is. For "*"
+// or a list of versions the current article is read: when it does not exist, or its version is not listed, the
+// precondition fails. Its version is passed on, so the change still fails when the article changes in between.
+func (h *handler) ifMatchVersion(ctx context.Context, id, header string) (int, error) {
+	if strings.TrimSpace(header) == "" {
+		return 0, nil
+	}
+
+	versions, wildcard := ifMatchVersions(header)
+	if !wildcard && len(versions) == 0 {
+		return 0, articles.ErrArticleVersionMismatch
+	}
+	if !wildcard && len(versions) == 1 {
+		return versions[0], nil
+	}
+
+	ar, err := h.ArticleService.Get(ctx, id, false, nil)
+	if err == articles.ErrArticleNotFound {
+		return 0, articles.ErrArticleVersionMismatch
+	}
+	if err != nil {
+		return 0, err
+	}
+	if wildcard {
+		return ar.Version, nil
+	}
+	for _, v := range versions {
+		if v == ar.Version {
+			return v, nil
+		}
+	}
+	return 0, articles.ErrArticleVersionMismatch
+}
+
+// notModified sets the validators of the representation on the response and evaluates the conditional
+// request headers against them (RFC 7232). A zero modified time leaves out Last-Modified, so only If-None-Match
+// is evaluated. When the client's copy is still current the response is completed with a 304 and true is
+// returned, so the caller can skip serializing the body.
+func notModified(c *gin.Context, tag string, modified time.Time) bool {
+	c.Header(etagHeader, tag)
+	if !modified.IsZero() {
+		c.Header(lastModifiedHeader, modified.UTC().Format(http.TimeFormat))
+	}
+
+	if inm := c.GetHeader(ifNoneMatchHeader); inm != "" {
+		if !etagListContains(inm, tag) {
+			return false
+		}
+	} else if ims := c.GetHeader(ifModifiedSinceHeader); ims != "" && !modified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err != nil || modified.Truncate(time.Second).After(since) {
+			return false
+		}
+	} else {
+		return false
+	}
+
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagListContains uses the weak comparison required by If-None-Match to look for tag in a header list
+func etagListContains(header, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
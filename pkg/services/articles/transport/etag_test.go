@@ -0,0 +1,46 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
+)
+
+func TestIfMatchVersions(t *testing.T) {
+	tests := map[string]struct {
+		header   string
+		versions []int
+		wildcard bool
+	}{
+		"Single":           {header: `"3"`, versions: []int{3}},
+		"List":             {header: `"3", "4" ,"5"`, versions: []int{3, 4, 5}},
+		"Any":              {header: ` * `, wildcard: true},
+		"Weak tags":        {header: `W/"3", "4"`, versions: []int{4}},
+		"Malformed tags":   {header: `3, "x", "0", "4`, versions: nil},
+		"Empty list item":  {header: `"3",,`, versions: []int{3}},
+		"Formats":          {header: `"3-json", "4-xml"`, versions: []int{3, 4}},
+		"Malformed format": {header: `"-json", "x-json"`, versions: nil},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			versions, wildcard := ifMatchVersions(test.header)
+
+			assert.Equal(t, test.versions, versions)
+			assert.Equal(t, test.wildcard, wildcard)
+		})
+	}
+}
+
+func TestETagFormats(t *testing.T) {
+	ar := articles.Article{ID: "a", Version: 3}
+	page := articles.Articles{Articles: []articles.Article{ar}}
+
+	assert.Equal(t, `"3-json"`, etag(ar, negotiate.MIMEJSON))
+	assert.Equal(t, `"3-xml"`, etag(ar, negotiate.MIMEXML))
+	assert.NotEqual(t, etag(ar, negotiate.MIMEJSON), etag(ar, negotiate.MIMEXML))
+	assert.NotEqual(t, listETag(page, negotiate.MIMEJSON), listETag(page, negotiate.MIMEXML))
+}
@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+// expandAuthor embeds the summary of the author in each article
+const expandAuthor = "author"
+
+// parseExpand reads the comma separated list of related resources to embed in article responses.
+// Only the author can be expanded for now.
+func parseExpand(value string) (bool, *errors.AppError) {
+	var author bool
+	for _, e := range strings.Split(value, ",") {
+		switch e = strings.TrimSpace(e); e {
+		case "":
+		case expandAuthor:
+			author = true
+		default:
+			return false, &errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("cannot expand %q", e), Field: "expand"}
+		}
+	}
+	return author, nil
+}
+
+// embedAuthors fills in the author summary of every article which is linked to an author,
+// looking up all the authors at once.
+func (h *handler) embedAuthors(ctx context.Context, al []articles.Article) error {
+	ids := make([]string, 0, len(al))
+	seen := make(map[string]bool)
+	for _, ar := range al {
+		if ar.AuthorID != nil && !seen[*ar.AuthorID] {
+			seen[*ar.AuthorID] = true
+			ids = append(ids, *ar.AuthorID)
+		}
+	}
+
+	summaries, err := h.AuthorService.Summaries(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range al {
+		if al[i].AuthorID == nil {
+			continue
+		}
+		if s, ok := summaries[*al[i].AuthorID]; ok {
+			al[i].Author = &s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package transport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/context"
+	"github.com/kott/go-service-example/pkg/utils/log"
+	"github.com/kott/go-service-example/pkg/utils/middleware"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
+)
+
+const exportPath = "/articles/export"
+
+// ExportContentTypes lets the export route be asked for the content types it exports past middleware.Negotiate
+var ExportContentTypes = middleware.RouteContentTypes{
+	Path:         exportPath,
+	ContentTypes: []string{ndjsonContentType, csvContentType},
+}
+
+// exportWriter writes the articles of an export to the response, one at a time
+type exportWriter interface {
+	begin() error
+	write(ar articles.Article) error
+	end() error
+}
+
+// exportFormat is a format articles can be exported in
+type exportFormat struct {
+	contentType string
+	newWriter   func(w io.Writer) exportWriter
+}
+
+var exportFormats = map[string]exportFormat{
+	"ndjson": {contentType: ndjsonContentType, newWriter: newNDJSONExport},
+	"csv":    {contentType: csvContentType, newWriter: newCSVExport},
+	"json":   {contentType: negotiate.MIMEJSON, newWriter: newJSONExport},
+}
+
+// exportFormatNames are the exportFormats in order of preference, the first being the default
+var exportFormatNames = []string{"ndjson", "csv", "json"}
+
+// negotiateExport picks the export format the Accept header prefers, NDJSON when it has no preference
+func negotiateExport(accept string) (string, bool) {
+	offered := make([]string, len(exportFormatNames))
+	for i, name := range exportFormatNames {
+		offered[i] = exportFormats[name].contentType
+	}
+
+	contentType, ok := negotiate.Select(accept, offered)
+	if !ok {
+		return "", false
+	}
+	for _, name := range exportFormatNames {
+		if exportFormats[name].contentType == contentType {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Export streams every live article, oldest first, in the format named by the format parameter or, without one, the
+// format negotiated from the Accept header. A format the Accept header does not allow is refused with a 406. Like
+// listings, only published articles are exported unless the caller opts in with includeUnpublished. Articles are
+// written as they are read from the database, so the export takes the same memory regardless of the number of
+// articles. Once the first article has been written the status can no longer change, so an export which fails after
+// that point, or whose client goes away, is cut short; a JSON array is then left unterminated.
+func (h *handler) Export(c *gin.Context) {
+	var q struct {
+		Format             string `form:"format"`
+		IncludeUnpublished bool   `form:"includeUnpublished,default=false"`
+	}
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	name := q.Format
+	if name == "" {
+		var ok bool
+		if name, ok = negotiateExport(c.GetHeader("Accept")); !ok {
+			log.Info(ctx, "no export format is acceptable: %s", c.GetHeader("Accept"))
+			negotiate.Render(c, http.StatusNotAcceptable,
+				errors.NewAppError(errors.NotAcceptable, errors.Descriptions[errors.NotAcceptable], "Accept"))
+			return
+		}
+	}
+
+	format, ok := exportFormats[name]
+	if !ok {
+		log.Info(ctx, "invalid export format: %s", name)
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, fmt.Sprintf("unknown export format %q", name), "format"))
+		return
+	}
+	if _, ok := negotiate.Select(c.GetHeader("Accept"), []string{format.contentType}); !ok {
+		log.Info(ctx, "export format %s is not acceptable: %s", name, c.GetHeader("Accept"))
+		negotiate.Render(c, http.StatusNotAcceptable,
+			errors.NewAppError(errors.NotAcceptable, fmt.Sprintf("the Accept header does not allow %s exports", name), "format"))
+		return
+	}
+
+	w := format.newWriter(c.Writer)
+	started := false
+	begin := func() error {
+		started = true
+		c.Header("Content-Type", format.contentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="articles.%s"`, name))
+		c.Status(http.StatusOK)
+		return w.begin()
+	}
+
+	count := 0
+	err := h.ArticleService.Export(ctx, q.IncludeUnpublished, func(ar articles.Article) error {
+		if !started {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		count++
+		return w.write(ar)
+	})
+	if err == nil && !started {
+		err = begin()
+	}
+
+	if err != nil && !started {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+	if err == nil {
+		err = w.end()
+	}
+	if err != nil {
+		log.Warn(ctx, "export cut short after %d articles: %s", count, err.Error())
+		return
+	}
+	log.Info(ctx, "exported %d articles", count)
+}
+
+// ndjsonExport writes every article as a JSON object on a line of its own
+type ndjsonExport struct {
+	enc *json.Encoder
+}
+
+func newNDJSONExport(w io.Writer) exportWriter {
+	return &ndjsonExport{enc: json.NewEncoder(w)}
+}
+
+func (e *ndjsonExport) begin() error { return nil }
+
+func (e *ndjsonExport) write(ar articles.Article) error { return e.enc.Encode(ar) }
+
+func (e *ndjsonExport) end() error { return nil }
+
+// jsonExport writes the articles as the elements of a single JSON array
+type jsonExport struct {
+	w   io.Writer
+	enc *json.Encoder
+	n   int
+}
+
+func newJSONExport(w io.Writer) exportWriter {
+	return &jsonExport{w: w, enc: json.NewEncoder(w)}
+}
+
+func (e *jsonExport) begin() error {
+	_, err := io.WriteString(e.w, "[\n")
+	return err
+}
+
+func (e *jsonExport) write(ar articles.Article) error {
+	if e.n > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.n++
+	return e.enc.Encode(ar)
+}
+
+func (e *jsonExport) end() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+// csvExportColumns are the columns of a CSV export. The columns shared with imports hold the same values, and the
+// others are csvIgnoredColumns, so a CSV export can be imported again.
+var csvExportColumns = []string{csvID, csvSlug, csvTitle, csvBody, csvTags, csvAuthorID, csvStatus, csvPublishAt, csvExpireAt, csvCreatedAt, csvUpdatedAt, csvVersion}
+
+// csvExport writes a header and then a record for every article
+type csvExport struct {
+	w *csv.Writer
+}
+
+func newCSVExport(w io.Writer) exportWriter {
+	return &csvExport{w: csv.NewWriter(w)}
+}
+
+func (e *csvExport) begin() error {
+	return e.w.Write(csvExportColumns)
+}
+
+func (e *csvExport) write(ar articles.Article) error {
+	var authorID string
+	if ar.AuthorID != nil {
+		authorID = *ar.AuthorID
+	}
+	return e.w.Write([]string{
+		ar.ID,
+		ar.Slug,
+		ar.Title,
+		ar.Body,
+		strings.Join(ar.Tags, csvTagSeparator),
+		authorID,
+		string(ar.Status),
+		formatCSVTime(ar.PublishAt),
+		formatCSVTime(ar.ExpireAt),
+		formatCSVTime(&ar.CreatedAt),
+		formatCSVTime(&ar.UpdatedAt),
+		strconv.Itoa(ar.Version),
+	})
+}
+
+func (e *csvExport) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// formatCSVTime writes an optional timestamp the way parseCSVTime reads it
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
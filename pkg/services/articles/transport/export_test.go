@@ -0,0 +1,200 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestHandlerExport(t *testing.T) {
+	created := time.Date(2021, 4, 2, 12, 0, 0, 0, time.UTC)
+	exported := []articles.Article{
+		{ID: "a", Slug: "first", Title: "first", Body: "multi\nline", Tags: []string{"go", "sql"}, Status: articles.StatusPublished, CreatedAt: created, UpdatedAt: created, Version: 1},
+		{ID: "b", Slug: "second", Title: "second", Body: "body", Status: articles.StatusDraft, CreatedAt: created, UpdatedAt: created, Version: 2},
+	}
+
+	tests := map[string]struct {
+		mockService *mockService
+		query       string
+		accept      string
+		contentType string
+		body        string
+		unpublished bool
+	}{
+		"NDJSON by default": {
+			mockService: &mockService{ExportResult: exported},
+			contentType: "application/x-ndjson",
+		},
+		"CSV": {
+			mockService: &mockService{ExportResult: exported},
+			query:       "?format=csv&includeUnpublished=true",
+			contentType: "text/csv",
+			body: "id,slug,title,body,tags,authorId,status,publishAt,expireAt,createdAt,updatedAt,version\n" +
+				"a,first,first,\"multi\nline\",go;sql,,published,,,2021-04-02T12:00:00Z,2021-04-02T12:00:00Z,1\n" +
+				"b,second,second,body,,,draft,,,2021-04-02T12:00:00Z,2021-04-02T12:00:00Z,2\n",
+			unpublished: true,
+		},
+		"JSON": {
+			mockService: &mockService{ExportResult: exported},
+			query:       "?format=json",
+			contentType: "application/json",
+		},
+		"Any format accepted": {
+			mockService: &mockService{ExportResult: exported},
+			accept:      "text/html, */*;q=0.8",
+			contentType: "application/x-ndjson",
+		},
+		"CSV negotiated": {
+			mockService: &mockService{ExportResult: exported},
+			query:       "?includeUnpublished=true",
+			accept:      "application/json;q=0.5, text/csv",
+			contentType: "text/csv",
+			body: "id,slug,title,body,tags,authorId,status,publishAt,expireAt,createdAt,updatedAt,version\n" +
+				"a,first,first,\"multi\nline\",go;sql,,published,,,2021-04-02T12:00:00Z,2021-04-02T12:00:00Z,1\n" +
+				"b,second,second,body,,,draft,,,2021-04-02T12:00:00Z,2021-04-02T12:00:00Z,2\n",
+			unpublished: true,
+		},
+		"JSON negotiated": {
+			mockService: &mockService{ExportResult: exported},
+			accept:      "application/json",
+			contentType: "application/json",
+		},
+		"Format within the accepted ones": {
+			mockService: &mockService{ExportResult: exported},
+			query:       "?format=json",
+			accept:      "text/csv, application/json;q=0.1",
+			contentType: "application/json",
+		},
+		"JSON without articles": {
+			mockService: &mockService{},
+			query:       "?format=json",
+			contentType: "application/json",
+			body:        "[\n]\n",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, "/articles/export"+test.query, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", test.accept)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.Equal(t, test.unpublished, test.mockService.ExportUnpublished)
+			assert.Equal(t, test.contentType, response.Header().Get("Content-Type"))
+			assert.Contains(t, response.Header().Get("Content-Disposition"), "attachment")
+
+			switch {
+			case test.body != "":
+				assert.Equal(t, test.body, response.Body.String())
+			case test.contentType == "application/json":
+				var ars []articles.Article
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &ars))
+				assert.Equal(t, exported, ars)
+			default:
+				dec := json.NewDecoder(response.Body)
+				for _, want := range exported {
+					var ar articles.Article
+					require.NoError(t, dec.Decode(&ar))
+					assert.Equal(t, want, ar)
+				}
+				assert.False(t, dec.More())
+			}
+		})
+	}
+}
+
+func TestHandlerExportFails(t *testing.T) {
+	tests := map[string]struct {
+		mockService *mockService
+		query       string
+		accept      string
+		response    errors.AppError
+		status      int
+	}{
+		"Unknown format": {
+			mockService: &mockService{},
+			query:       "?format=xml",
+			response:    errors.AppError{Code: errors.BadRequest, Description: `unknown export format "xml"`, Field: "format"},
+			status:      http.StatusBadRequest,
+		},
+		"Format not accepted": {
+			mockService: &mockService{ExportResult: []articles.Article{{ID: "a"}}},
+			query:       "?format=csv",
+			accept:      "application/json",
+			response:    errors.AppError{Code: errors.NotAcceptable, Description: "the Accept header does not allow csv exports", Field: "format"},
+			status:      http.StatusNotAcceptable,
+		},
+		"Service error": {
+			mockService: &mockService{ExportErr: articles.ErrArticleQuery},
+			response:    errors.AppError{Code: errors.InternalServerError, Description: articles.ErrArticleQuery.Error(), Field: "unknown"},
+			status:      http.StatusInternalServerError,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, "/articles/export"+test.query, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", test.accept)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			var appErr errors.AppError
+			if err := json.Unmarshal(response.Body.Bytes(), &appErr); err != nil {
+				assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+			}
+			assert.Equal(t, test.response, appErr)
+		})
+	}
+}
+
+func TestCSVExportImports(t *testing.T) {
+	authorID := uuid.New().String()
+	created := time.Date(2021, 4, 2, 12, 0, 0, 0, time.UTC)
+	exported := []articles.Article{
+		{ID: "a", Slug: "first", Title: "first", Body: "multi\nline", Tags: []string{"go", "sql"}, AuthorID: &authorID, Status: articles.StatusPublished, PublishAt: &created, CreatedAt: created, UpdatedAt: created, Version: 3},
+		{ID: "b", Slug: "second", Title: "second", Body: "body", Status: articles.StatusDraft, CreatedAt: created, UpdatedAt: created, Version: 1},
+	}
+
+	var buf bytes.Buffer
+	w := newCSVExport(&buf)
+	require.NoError(t, w.begin())
+	for _, ar := range exported {
+		require.NoError(t, w.write(ar))
+	}
+	require.NoError(t, w.end())
+
+	r, appErr := newCSVReader(&buf)
+	require.Nil(t, appErr)
+	records, err := readAll(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, []articles.ImportRecord{
+		{Line: 2, Article: articles.ArticleCreateUpdate{Title: "first", Body: "multi\nline", Tags: []string{"go", "sql"}, AuthorID: &authorID, PublishAt: &created}},
+		{Line: 3, Article: articles.ArticleCreateUpdate{Title: "second", Body: "body"}},
+	}, records)
+}
@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+// parseFields reads the comma separated list of fields article responses are narrowed down to, e.g.
+// "id,title,updatedAt". Every unknown field is named in the error.
+func parseFields(value string) ([]string, *errors.AppError) {
+	var fields, unknown []string
+	seen := make(map[string]bool)
+	for _, f := range strings.Split(value, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" || seen[f] {
+			continue
+		}
+		seen[f] = true
+		if !articles.Fields[f] {
+			unknown = append(unknown, fmt.Sprintf("%q", f))
+			continue
+		}
+		fields = append(fields, f)
+	}
+
+	if len(unknown) > 0 {
+		return nil, &errors.AppError{Code: errors.BadRequest, Description: "unknown fields " + strings.Join(unknown, ", "), Field: "fields"}
+	}
+	return fields, nil
+}
+
+// wants reports whether the field is part of the response, which every field is when none were asked for
+func wants(fields []string, field string) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonField is a field of the JSON representation of an article
+type jsonField struct {
+	index     int
+	omitEmpty bool
+}
+
+// articleJSONFields maps the JSON names of the fields of an article onto the fields
+var articleJSONFields = func() map[string]jsonField {
+	t := reflect.TypeOf(articles.Article{})
+	fields := make(map[string]jsonField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")
+		fields[tag[0]] = jsonField{index: i, omitEmpty: len(tag) > 1 && tag[1] == "omitempty"}
+	}
+	return fields
+}()
+
+// sparse narrows the article down to the fields. Empty fields which are left out of the
+// JSON representation of an article are left out here as well.
+func sparse(ar articles.Article, fields []string) map[string]interface{} {
+	v := reflect.ValueOf(ar)
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		jf := articleJSONFields[f]
+		fv := v.Field(jf.index)
+		if jf.omitEmpty && fv.IsZero() {
+			continue
+		}
+		m[f] = fv.Interface()
+	}
+	return m
+}
+
+// sparseArticles is a page of articles narrowed down to the fields asked for
+type sparseArticles struct {
+	Articles []map[string]interface{} `json:"articles"`
+	articles.PageInfo
+}
+
+func sparsePage(page articles.Articles, fields []string) sparseArticles {
+	sp := sparseArticles{Articles: make([]map[string]interface{}, len(page.Articles)), PageInfo: page.PageInfo}
+	for i, ar := range page.Articles {
+		sp.Articles[i] = sparse(ar, fields)
+	}
+	return sp
+}
@@ -0,0 +1,148 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestParseFields(t *testing.T) {
+	tests := map[string]struct {
+		value  string
+		fields []string
+		err    *errors.AppError
+	}{
+		"Empty":      {value: "", fields: nil},
+		"Fields":     {value: "id, title,updatedAt", fields: []string{"id", "title", "updatedAt"}},
+		"Duplicates": {value: "id,id,,title", fields: []string{"id", "title"}},
+		"Unknown fields": {
+			value: "id,summary,title,Body",
+			err:   &errors.AppError{Code: errors.BadRequest, Description: `unknown fields "summary", "Body"`, Field: "fields"},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			fields, err := parseFields(test.value)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.fields, fields)
+		})
+	}
+}
+
+func TestArticleJSONFields(t *testing.T) {
+	for f := range articles.Fields {
+		_, ok := articleJSONFields[f]
+		assert.True(t, ok, f)
+	}
+}
+
+func TestHandlerGetFields(t *testing.T) {
+	id := uuid.New().String()
+	updated := time.Date(2021, 4, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		mockService *mockService
+		uri         string
+		fields      []string
+		response    string
+		status      int
+	}{
+		"Fields": {
+			mockService: &mockService{GetResult: articles.Article{ID: id, Title: "title", UpdatedAt: updated, Version: 2, Status: articles.StatusPublished}},
+			uri:         "/articles/" + id + "?fields=id,title,updatedAt",
+			fields:      []string{"id", "title", "updatedAt"},
+			response:    `{"id":"` + id + `","title":"title","updatedAt":"2021-04-02T12:00:00Z"}`,
+			status:      http.StatusOK,
+		},
+		"Empty optional fields are left out": {
+			mockService: &mockService{GetResult: articles.Article{ID: id, Version: 2, Status: articles.StatusPublished}},
+			uri:         "/articles/" + id + "?fields=id,publishAt,authorId",
+			fields:      []string{"id", "publishAt", "authorId"},
+			response:    `{"id":"` + id + `"}`,
+			status:      http.StatusOK,
+		},
+		"HTML is only rendered when asked for": {
+			mockService: &mockService{GetResult: articles.Article{ID: id, Version: 2, Status: articles.StatusPublished}, RenderErr: articles.ErrArticleRender},
+			uri:         "/articles/" + id + "?fields=id&format=html",
+			fields:      []string{"id"},
+			response:    `{"id":"` + id + `"}`,
+			status:      http.StatusOK,
+		},
+		"Unknown fields": {
+			mockService: &mockService{},
+			uri:         "/articles/" + id + "?fields=id,summary",
+			response:    `{"code":"BAD_REQUEST","description":"unknown fields \"summary\"","field":"fields"}`,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+			assert.Equal(t, test.fields, test.mockService.GetFields)
+			assert.JSONEq(t, test.response, response.Body.String())
+		})
+	}
+}
+
+func TestHandlerGetAllFields(t *testing.T) {
+	id := uuid.New().String()
+	svc := &mockService{GetAllResult: articles.Articles{
+		Articles: []articles.Article{{ID: id, Title: "title", Body: "body", Version: 1}},
+		PageInfo: articles.PageInfo{Limit: 25, NextCursor: "next"},
+	}}
+
+	response := httptest.NewRecorder()
+	router := gin.New()
+	newHandler(router, svc, &mockAuthorService{}, Config{})
+
+	req, err := http.NewRequest(http.MethodGet, "/articles/?fields=id,title", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(response, req)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, []string{"id", "title"}, svc.GetAllParams.Fields)
+
+	var page sparseArticles
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+	assert.Equal(t, sparseArticles{
+		Articles: []map[string]interface{}{{"id": id, "title": "title"}},
+		PageInfo: articles.PageInfo{Limit: 25, NextCursor: "next"},
+	}, page)
+}
+
+func TestHandlerSearchFields(t *testing.T) {
+	response := httptest.NewRecorder()
+	router := gin.New()
+	newHandler(router, &mockService{}, &mockAuthorService{}, Config{})
+
+	req, err := http.NewRequest(http.MethodGet, "/articles/?q=go&fields=id", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(response, req)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), `"field": "fields"`)
+}
@@ -9,68 +9,264 @@ import (
 	"github.com/kott/go-service-example/pkg/errors"
 	"github.com/kott/go-service-example/pkg/services/articles"
 	"github.com/kott/go-service-example/pkg/services/articles/store"
+	"github.com/kott/go-service-example/pkg/services/authors"
+	authorstore "github.com/kott/go-service-example/pkg/services/authors/store"
 	"github.com/kott/go-service-example/pkg/utils/context"
 	"github.com/kott/go-service-example/pkg/utils/log"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
+	"github.com/kott/go-service-example/pkg/utils/paging"
 )
 
+// defaultSimilarityThreshold matches the default of pg_trgm.similarity_threshold
+const defaultSimilarityThreshold = 0.3
+
+// Config holds the settings of the article endpoints
+type Config struct {
+	// SimilarityThreshold is the minimum trigram similarity (0-1] for an article to be a search hit
+	SimilarityThreshold float64
+}
+
 type handler struct {
 	ArticleService articles.Service
+	AuthorService  authors.Service
+	Config         Config
 }
 
 // Activate sets all the services required for articles and registers all the endpoints with the engine.
-func Activate(router *gin.Engine, db *sql.DB) {
+func Activate(router *gin.Engine, db *sql.DB, cfg Config) {
 	articleService := articles.New(store.New(db))
-	newHandler(router, articleService)
+	authorService := authors.New(authorstore.New(db))
+	newHandler(router, articleService, authorService, cfg)
 }
 
-func newHandler(router *gin.Engine, as articles.Service) {
+func newHandler(router *gin.Engine, as articles.Service, aus authors.Service, cfg Config) {
+	if cfg.SimilarityThreshold <= 0 || cfg.SimilarityThreshold > 1 {
+		cfg.SimilarityThreshold = defaultSimilarityThreshold
+	}
+
 	h := handler{
 		ArticleService: as,
+		AuthorService:  aus,
+		Config:         cfg,
 	}
 
 	router.GET("/articles/:id", h.Get)
 	router.GET("/articles/", h.GetAll)
 	router.POST("/articles/", h.Create)
 	router.PUT("/articles/:id", h.Update)
+	router.PATCH("/articles/:id", h.Patch)
+	router.DELETE("/articles/:id", h.Delete)
+	router.POST("/articles/batch", h.Batch)
+	router.POST(importPath, h.Import)
+
+	for name, t := range articles.Transitions {
+		router.POST("/articles/:id/"+name, h.transition(t))
+	}
+
+	router.GET("/articles/trash", h.Trash)
+	router.POST("/articles/:id/restore", h.Restore)
+
+	router.GET("/articles/search", h.Search)
+	router.GET(exportPath, h.Export)
+
+	router.GET("/articles/:id/revisions", h.Revisions)
+	router.GET("/articles/:id/revisions/:rev", h.Revision)
+	router.POST("/articles/:id/revisions/:rev/revert", h.Revert)
+	router.GET("/articles/:id/diff", h.Diff)
+
+	router.GET("/tags", h.Tags)
+
+	router.GET("/authors/:id/articles", h.AuthorArticles)
 }
 
+// Get responds with the article. Like listings, it only serves published articles unless the caller opts
+// in with includeUnpublished, the way disabled articles are only served with includeDisabled. As of a time,
+// the article has to have been published then.
 func (h *handler) Get(c *gin.Context) {
+	var q struct {
+		IncludeDisabled    bool   `form:"includeDisabled,default=false"`
+		IncludeUnpublished bool   `form:"includeUnpublished,default=false"`
+		AsOf               string `form:"asOf"`
+		Expand             string `form:"expand"`
+		Format             string `form:"format"`
+		Fields             string `form:"fields"`
+	}
+
 	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	asOf, appErr := parseAsOf(q.AsOf)
+	if appErr != nil {
+		log.Info(ctx, "invalid asOf: %s", q.AsOf)
+		negotiate.Render(c, http.StatusBadRequest, appErr)
+		return
+	}
+
+	expand, appErr := parseExpand(q.Expand)
+	if appErr != nil {
+		log.Info(ctx, "invalid expand: %s", q.Expand)
+		negotiate.Render(c, http.StatusBadRequest, appErr)
+		return
+	}
+
+	html, appErr := parseFormat(q.Format)
+	if appErr != nil {
+		log.Info(ctx, "invalid format: %s", q.Format)
+		negotiate.Render(c, http.StatusBadRequest, appErr)
+		return
+	}
+
+	fields, appErr := parseFields(q.Fields)
+	if appErr != nil {
+		log.Info(ctx, "invalid fields: %s", q.Fields)
+		negotiate.Render(c, http.StatusBadRequest, appErr)
+		return
+	}
+	html = html && wants(fields, "bodyHtml")
+	expand = expand && wants(fields, "author")
 
-	log.Info(ctx, "retrieving article id=%s", c.Param("id"))
-	article, err := h.ArticleService.Get(ctx, c.Param("id"))
+	id, ok := h.articleID(c)
+	if !ok {
+		return
+	}
+
+	var article articles.Article
+	var err error
+	if asOf != nil {
+		log.Info(ctx, "retrieving article id=%s as of %s", id, q.AsOf)
+		article, err = h.ArticleService.GetAsOf(ctx, id, *asOf, q.IncludeDisabled)
+	} else {
+		log.Info(ctx, "retrieving article id=%s", id)
+		article, err = h.ArticleService.Get(ctx, id, q.IncludeDisabled, fields)
+	}
+	if err == nil && !q.IncludeUnpublished && article.Status != articles.StatusPublished {
+		log.Info(ctx, "article id=%s is %s, not published", id, article.Status)
+		err = articles.ErrArticleNotFound
+	}
+	if err == nil && html {
+		article, err = h.ArticleService.Render(ctx, article)
+	}
 	if err != nil {
 		status, appErr := handleError(err)
-		c.IndentedJSON(status, appErr)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	// the version of an article does not cover its author, and an earlier state of an article comes with the
+	// tags it has now, so neither expanded responses nor point-in-time reads are subject to conditional requests
+	if expand {
+		al := []articles.Article{article}
+		if err := h.embedAuthors(ctx, al); err != nil {
+			status, appErr := handleError(err)
+			negotiate.Render(c, status, appErr)
+			return
+		}
+		article = al[0]
+	} else if asOf == nil && notModified(c, etag(article, negotiate.GetFormat(c)), article.UpdatedAt) {
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, article)
+	if len(fields) > 0 {
+		negotiate.Render(c, http.StatusOK, sparse(article, fields))
+		return
+	}
+	negotiate.Render(c, http.StatusOK, article)
 }
 
 func (h *handler) GetAll(c *gin.Context) {
-	var q struct {
-		Limit  int `form:"limit,default=25"`
-		Offset int `form:"offset,default=0"`
+	var q listQuery
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
 	}
 
+	if q.Q != "" {
+		sq, errs := q.searchQuery()
+		if len(errs) > 0 {
+			log.Info(ctx, "invalid list query: %v", errs)
+			negotiate.Render(c, http.StatusBadRequest, errors.AppErrors{Errors: errs})
+			return
+		}
+		h.search(c, sq)
+		return
+	}
+
+	h.list(c, q, "")
+}
+
+func (h *handler) AuthorArticles(c *gin.Context) {
+	var q listQuery
+
 	ctx := context.GetReqCtx(c)
 	if err := c.BindQuery(&q); err != nil {
 		log.Info(ctx, "query parse error: %s", err.Error())
-		c.IndentedJSON(http.StatusBadRequest,
+		negotiate.Render(c, http.StatusBadRequest,
 			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
 		return
 	}
 
-	log.Info(ctx, "retrieving all articles: offset=%d limit=%d", q.Limit, q.Offset)
-	artcls, err := h.ArticleService.GetAll(ctx, q.Limit, q.Offset)
+	id := c.Param("id")
+	if _, err := h.AuthorService.Get(ctx, id); err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	h.list(c, q, id)
+}
+
+// list responds with a page of articles, narrowed down to those of the author unless authorID is empty,
+// and links to the neighbouring pages in the Link header
+func (h *handler) list(c *gin.Context, q listQuery, authorID string) {
+	ctx := context.GetReqCtx(c)
+
+	params, errs := q.params()
+	expand, appErr := parseExpand(q.Expand)
+	if appErr != nil {
+		errs = append(errs, *appErr)
+	}
+	expand = expand && wants(params.Fields, "author")
+	if len(errs) > 0 {
+		log.Info(ctx, "invalid list query: %v", errs)
+		negotiate.Render(c, http.StatusBadRequest, errors.AppErrors{Errors: errs})
+		return
+	}
+	params.AuthorID = authorID
+
+	log.Info(ctx, "retrieving all articles: offset=%d limit=%d cursor=%s author=%s", params.Offset, params.Limit, q.Cursor, authorID)
+	page, err := h.ArticleService.GetAll(ctx, params)
 	if err != nil {
 		status, appErr := handleError(err)
-		c.IndentedJSON(status, appErr)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+	c.Header(linkHeader, pageLinks(c.Request.URL, page))
+
+	// as with a single article, neither expanded nor point-in-time listings are subject to conditional requests
+	if expand {
+		if err := h.embedAuthors(ctx, page.Articles); err != nil {
+			status, appErr := handleError(err)
+			negotiate.Render(c, status, appErr)
+			return
+		}
+	} else if params.AsOf == nil && notModified(c, listETag(page, negotiate.GetFormat(c)), lastModified(page.Articles...)) {
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, articles.Articles{Articles: artcls})
+	if len(params.Fields) > 0 {
+		negotiate.Render(c, http.StatusOK, sparsePage(page, params.Fields))
+		return
+	}
+	negotiate.Render(c, http.StatusOK, page)
 }
 
 func (h *handler) Create(c *gin.Context) {
@@ -79,7 +275,7 @@ func (h *handler) Create(c *gin.Context) {
 	var ac articles.ArticleCreateUpdate
 	if err := c.ShouldBindJSON(&ac); err != nil {
 		log.Info(ctx, "request parse error: %s", err.Error())
-		c.IndentedJSON(http.StatusBadRequest,
+		negotiate.Render(c, http.StatusBadRequest,
 			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
 		return
 	}
@@ -88,11 +284,12 @@ func (h *handler) Create(c *gin.Context) {
 	article, err := h.ArticleService.Create(ctx, ac)
 	if err != nil {
 		status, appErr := handleError(err)
-		c.IndentedJSON(status, appErr)
+		negotiate.Render(c, status, appErr)
 		return
 	}
 
-	c.IndentedJSON(http.StatusCreated, article)
+	c.Header(etagHeader, etag(article, negotiate.GetFormat(c)))
+	negotiate.Render(c, http.StatusCreated, article)
 }
 
 func (h *handler) Update(c *gin.Context) {
@@ -101,22 +298,377 @@ func (h *handler) Update(c *gin.Context) {
 	var ac articles.ArticleCreateUpdate
 	if err := c.ShouldBindJSON(&ac); err != nil {
 		log.Info(ctx, "request parse error: %s", err.Error())
-		c.IndentedJSON(http.StatusBadRequest,
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	id := c.Param("id")
+
+	version, err := h.ifMatchVersion(ctx, id, c.GetHeader(ifMatchHeader))
+	if err != nil {
+		log.Info(ctx, "unmet If-Match header: %s", c.GetHeader(ifMatchHeader))
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	log.Info(ctx, "updating article %s (version=%d) with %v", id, version, ac)
+	article, err := h.ArticleService.Update(ctx, ac, id, version)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	c.Header(etagHeader, etag(article, negotiate.GetFormat(c)))
+	negotiate.Render(c, http.StatusOK, article)
+}
+
+func (h *handler) Patch(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+
+	if ct := c.ContentType(); ct != mergePatchContentType && ct != "application/json" {
+		log.Info(ctx, "unsupported patch content type: %s", ct)
+		negotiate.Render(c, http.StatusUnsupportedMediaType,
+			errors.NewAppError(errors.UnsupportedMediaType, errors.Descriptions[errors.UnsupportedMediaType], "Content-Type"))
+		return
+	}
+
+	data, err := c.GetRawData()
+	if err != nil {
+		log.Info(ctx, "request read error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
 			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
 		return
 	}
 
+	p, err := parseMergePatch(data)
+	if err != nil {
+		log.Info(ctx, "request parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest, err)
+		return
+	}
+
 	id := c.Param("id")
 
-	log.Info(ctx, "updating article %s with %v", id, ac)
-	article, err := h.ArticleService.Update(ctx, ac, id)
+	version, err := h.ifMatchVersion(ctx, id, c.GetHeader(ifMatchHeader))
+	if err != nil {
+		log.Info(ctx, "unmet If-Match header: %s", c.GetHeader(ifMatchHeader))
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	log.Info(ctx, "patching article %s (version=%d)", id, version)
+	article, err := h.ArticleService.Patch(ctx, p, id, version)
 	if err != nil {
 		status, appErr := handleError(err)
-		c.IndentedJSON(status, appErr)
+		negotiate.Render(c, status, appErr)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, article)
+	c.Header(etagHeader, etag(article, negotiate.GetFormat(c)))
+	negotiate.Render(c, http.StatusOK, article)
+}
+
+func (h *handler) Delete(c *gin.Context) {
+	var q struct {
+		Permanent bool `form:"permanent,default=false"`
+	}
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	id := c.Param("id")
+
+	log.Info(ctx, "deleting article %s permanent=%t", id, q.Permanent)
+	del := h.ArticleService.Delete
+	if q.Permanent {
+		del = h.ArticleService.Purge
+	}
+	if err := del(ctx, id); err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// transition handles the endpoint which moves an article along the workflow by making t
+func (h *handler) transition(t articles.Transition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.GetReqCtx(c)
+
+		id := c.Param("id")
+
+		version, err := h.ifMatchVersion(ctx, id, c.GetHeader(ifMatchHeader))
+		if err != nil {
+			log.Info(ctx, "unmet If-Match header: %s", c.GetHeader(ifMatchHeader))
+			status, appErr := handleError(err)
+			negotiate.Render(c, status, appErr)
+			return
+		}
+
+		log.Info(ctx, "%s article %s (version=%d)", t.Name, id, version)
+		article, err := h.ArticleService.Transition(ctx, id, t, version)
+		if err != nil {
+			status, appErr := handleError(err)
+			negotiate.Render(c, status, appErr)
+			return
+		}
+
+		c.Header(etagHeader, etag(article, negotiate.GetFormat(c)))
+		negotiate.Render(c, http.StatusOK, article)
+	}
+}
+
+func (h *handler) Trash(c *gin.Context) {
+	var q paging.Query
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	if errs := q.Validate(); len(errs) > 0 {
+		log.Info(ctx, "invalid list query: %v", errs)
+		negotiate.Render(c, http.StatusBadRequest, errors.AppErrors{Errors: errs})
+		return
+	}
+
+	log.Info(ctx, "retrieving disabled articles: offset=%d limit=%d", q.Offset, q.Limit)
+	artcls, err := h.ArticleService.Trash(ctx, q.Limit, q.Offset)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, articles.Articles{Articles: artcls})
+}
+
+func (h *handler) Restore(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+	id := c.Param("id")
+
+	log.Info(ctx, "restoring article %s", id)
+	article, err := h.ArticleService.Restore(ctx, id)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	c.Header(etagHeader, etag(article, negotiate.GetFormat(c)))
+	negotiate.Render(c, http.StatusOK, article)
+}
+
+func (h *handler) Search(c *gin.Context) {
+	var q searchQuery
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	h.search(c, q)
+}
+
+// search responds with a page of hits and links to the neighbouring pages in the Link header
+func (h *handler) search(c *gin.Context, q searchQuery) {
+	ctx := context.GetReqCtx(c)
+
+	params, errs := q.params(h.Config.SimilarityThreshold)
+	if len(errs) > 0 {
+		log.Info(ctx, "invalid search query: %v", errs)
+		negotiate.Render(c, http.StatusBadRequest, errors.AppErrors{Errors: errs})
+		return
+	}
+
+	log.Info(ctx, "searching articles: q=%s mode=%s body=%t offset=%d limit=%d", params.Query, q.Mode, params.IncludeBody, params.Offset, params.Limit)
+	page, err := h.ArticleService.Search(ctx, params)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+	c.Header(linkHeader, pageLinks(c.Request.URL, page))
+
+	negotiate.Render(c, http.StatusOK, page)
+}
+
+// Revisions responds with the history of an article, which is only served for published articles unless the
+// caller opts in with includeUnpublished, as the article itself is
+func (h *handler) Revisions(c *gin.Context) {
+	var q struct {
+		paging.Query
+		IncludeUnpublished bool `form:"includeUnpublished,default=false"`
+	}
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	if errs := q.Validate(); len(errs) > 0 {
+		log.Info(ctx, "invalid list query: %v", errs)
+		negotiate.Render(c, http.StatusBadRequest, errors.AppErrors{Errors: errs})
+		return
+	}
+
+	id := c.Param("id")
+
+	log.Info(ctx, "retrieving revisions of article %s: offset=%d limit=%d", id, q.Offset, q.Limit)
+	revs, err := h.ArticleService.Revisions(ctx, id, q.IncludeUnpublished, q.Limit, q.Offset)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, revs)
+}
+
+// Revision responds with a single revision of an article, published unless the caller opts in with includeUnpublished
+func (h *handler) Revision(c *gin.Context) {
+	var q struct {
+		IncludeUnpublished bool `form:"includeUnpublished,default=false"`
+	}
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	rev, appErr := parseRevision(c.Param("rev"), "rev")
+	if appErr != nil {
+		log.Info(ctx, "invalid revision: %s", c.Param("rev"))
+		negotiate.Render(c, http.StatusBadRequest, appErr)
+		return
+	}
+
+	id := c.Param("id")
+
+	log.Info(ctx, "retrieving revision %d of article %s", rev, id)
+	revision, err := h.ArticleService.Revision(ctx, id, q.IncludeUnpublished, rev)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, revision)
+}
+
+func (h *handler) Revert(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+
+	rev, appErr := parseRevision(c.Param("rev"), "rev")
+	if appErr != nil {
+		log.Info(ctx, "invalid revision: %s", c.Param("rev"))
+		negotiate.Render(c, http.StatusBadRequest, appErr)
+		return
+	}
+
+	id := c.Param("id")
+
+	version, err := h.ifMatchVersion(ctx, id, c.GetHeader(ifMatchHeader))
+	if err != nil {
+		log.Info(ctx, "unmet If-Match header: %s", c.GetHeader(ifMatchHeader))
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	log.Info(ctx, "reverting article %s (version=%d) to revision %d", id, version, rev)
+	article, err := h.ArticleService.Revert(ctx, id, rev, version)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	c.Header(etagHeader, etag(article, negotiate.GetFormat(c)))
+	negotiate.Render(c, http.StatusOK, article)
+}
+
+// Diff compares two revisions of an article, published unless the caller opts in with includeUnpublished
+func (h *handler) Diff(c *gin.Context) {
+	var q diffQuery
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	from, to, unit, errs := q.params()
+	if len(errs) > 0 {
+		log.Info(ctx, "invalid diff query: %v", errs)
+		negotiate.Render(c, http.StatusBadRequest, errors.AppErrors{Errors: errs})
+		return
+	}
+
+	id := c.Param("id")
+
+	log.Info(ctx, "comparing revisions %d and %d of article %s by %s", from, to, id, q.Unit)
+	diff, err := h.ArticleService.Diff(ctx, id, q.IncludeUnpublished, from, to, unit)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, diff)
+}
+
+func (h *handler) Tags(c *gin.Context) {
+	var q paging.Query
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	if errs := q.Validate(); len(errs) > 0 {
+		log.Info(ctx, "invalid list query: %v", errs)
+		negotiate.Render(c, http.StatusBadRequest, errors.AppErrors{Errors: errs})
+		return
+	}
+
+	log.Info(ctx, "retrieving tags: offset=%d limit=%d", q.Offset, q.Limit)
+	tags, err := h.ArticleService.Tags(ctx, q.Limit, q.Offset)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, tags)
 }
 
 // handleError allows us to map errors defined internally to appropriate HTTP error codes and JSON responses
@@ -124,10 +676,34 @@ func handleError(e error) (int, error) {
 	switch e {
 	case articles.ErrArticleNotFound:
 		return http.StatusNotFound, errors.NewAppError(errors.NotFound, e.Error(), "id")
+	case articles.ErrArticleAuthorNotFound:
+		return http.StatusNotFound, errors.NewAppError(errors.NotFound, e.Error(), "authorId")
+	case authors.ErrAuthorNotFound:
+		return http.StatusNotFound, errors.NewAppError(errors.NotFound, e.Error(), "id")
+	case articles.ErrInvalidSchedule:
+		return http.StatusBadRequest, errors.NewAppError(errors.BadRequest, e.Error(), "expireAt")
+	case articles.ErrRevisionNotFound:
+		return http.StatusNotFound, errors.NewAppError(errors.NotFound, e.Error(), "rev")
 	case articles.ErrArticleUpdate:
 		fallthrough
 	case articles.ErrArticleCreate:
 		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, "unable to create/update article", "")
+	case articles.ErrArticleVersionMismatch:
+		return http.StatusPreconditionFailed, errors.NewAppError(errors.PreconditionFailed, e.Error(), ifMatchHeader)
+	case articles.ErrArticleNotDisabled:
+		return http.StatusForbidden, errors.NewAppError(errors.ForbiddenAction, e.Error(), "id")
+	case articles.ErrInvalidTransition:
+		return http.StatusForbidden, errors.NewAppError(errors.ForbiddenAction, e.Error(), "status")
+	case articles.ErrArticleDelete:
+		fallthrough
+	case articles.ErrArticlePurge:
+		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, "unable to delete article", "")
+	case articles.ErrArticleRestore:
+		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, "unable to restore article", "")
+	case articles.ErrImportRead:
+		return http.StatusBadRequest, errors.NewAppError(errors.BadRequest, e.Error(), "")
+	case articles.ErrArticleRender:
+		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, e.Error(), "format")
 	default:
 		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, e.Error(), "unknown")
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -17,24 +18,91 @@ import (
 
 	"github.com/kott/go-service-example/pkg/errors"
 	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/services/authors"
+	"github.com/kott/go-service-example/pkg/utils/middleware"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
 )
 
 type mockService struct {
-	GetResult    articles.Article
-	GetErr       error
-	GetAllResult []articles.Article
-	GetAllErr    error
-	CreateResult articles.Article
-	CreateErr    error
-	UpdateResult articles.Article
-	UpdateErr    error
+	GetResult            articles.Article
+	GetErr               error
+	GetFields            []string
+	GetAsOfResult        articles.Article
+	GetAsOfErr           error
+	ResolveSlugID        string
+	ResolveSlugSlug      string
+	ResolveSlugErr       error
+	RenderHTML           string
+	RenderErr            error
+	GetAllResult         articles.Articles
+	GetAllErr            error
+	GetAllParams         articles.ListParams
+	CreateResult         articles.Article
+	CreateErr            error
+	UpdateResult         articles.Article
+	UpdateErr            error
+	PatchResult          articles.Article
+	PatchErr             error
+	BatchResult          []articles.BatchResult
+	BatchErr             error
+	BatchOps             []articles.BatchOperation
+	BatchAtomic          bool
+	ImportRecords        []articles.ImportRecord
+	ImportReadErr        error
+	ImportFailures       []articles.ImportFailure
+	ImportResult         articles.ImportProgress
+	ImportErr            error
+	ExportResult         []articles.Article
+	ExportUnpublished    bool
+	ExportErr            error
+	DeleteErr            error
+	TransitionResult     articles.Article
+	TransitionErr        error
+	TransitionInput      articles.Transition
+	TrashResult          []articles.Article
+	TrashErr             error
+	RestoreResult        articles.Article
+	RestoreErr           error
+	PurgeErr             error
+	SearchResult         articles.Articles
+	SearchErr            error
+	SearchParams         articles.SearchParams
+	RevisionsResult      articles.Revisions
+	RevisionsUnpublished bool
+	RevisionsErr         error
+	RevisionResult       articles.Revision
+	RevisionErr          error
+	RevertResult         articles.Article
+	RevertErr            error
+	DiffResult           articles.Diff
+	DiffErr              error
+	TagsResult           articles.Tags
+	TagsErr              error
 }
 
-func (s *mockService) Get(ctx context.Context, id string) (articles.Article, error) {
+func (s *mockService) Get(ctx context.Context, id string, includeDisabled bool, fields []string) (articles.Article, error) {
+	s.GetFields = fields
 	return s.GetResult, s.GetErr
 }
 
-func (s *mockService) GetAll(ctx context.Context, limit, offset int) ([]articles.Article, error) {
+func (s *mockService) GetAsOf(ctx context.Context, id string, at time.Time, includeDisabled bool) (articles.Article, error) {
+	return s.GetAsOfResult, s.GetAsOfErr
+}
+
+func (s *mockService) ResolveSlug(ctx context.Context, slug string) (string, string, error) {
+	return s.ResolveSlugID, s.ResolveSlugSlug, s.ResolveSlugErr
+}
+
+func (s *mockService) Render(ctx context.Context, ar articles.Article) (articles.Article, error) {
+	if s.RenderErr != nil {
+		return articles.Article{}, s.RenderErr
+	}
+	ar.BodyHTML = s.RenderHTML
+	return ar, nil
+}
+
+func (s *mockService) GetAll(ctx context.Context, p articles.ListParams) (articles.Articles, error) {
+	s.GetAllParams = p
 	return s.GetAllResult, s.GetAllErr
 }
 
@@ -42,10 +110,127 @@ func (s *mockService) Create(ctx context.Context, ar articles.ArticleCreateUpdat
 	return s.CreateResult, s.CreateErr
 }
 
-func (s *mockService) Update(ctx context.Context, ar articles.ArticleCreateUpdate, id string) (articles.Article, error) {
+func (s *mockService) Update(ctx context.Context, ar articles.ArticleCreateUpdate, id string, version int) (articles.Article, error) {
 	return s.UpdateResult, s.UpdateErr
 }
 
+func (s *mockService) Patch(ctx context.Context, p articles.ArticlePatch, id string, version int) (articles.Article, error) {
+	return s.PatchResult, s.PatchErr
+}
+
+func (s *mockService) Batch(ctx context.Context, ops []articles.BatchOperation, atomic bool) ([]articles.BatchResult, error) {
+	s.BatchOps = ops
+	s.BatchAtomic = atomic
+	return s.BatchResult, s.BatchErr
+}
+
+// Import drains the reader into ImportRecords and reports ImportFailures along with ImportResult
+func (s *mockService) Import(ctx context.Context, r articles.ImportReader, report func(articles.ImportProgress, []articles.ImportFailure)) (articles.ImportProgress, error) {
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			s.ImportReadErr = err
+			break
+		}
+		s.ImportRecords = append(s.ImportRecords, rec)
+	}
+	report(s.ImportResult, s.ImportFailures)
+	return s.ImportResult, s.ImportErr
+}
+
+func (s *mockService) Export(ctx context.Context, includeUnpublished bool, fn func(articles.Article) error) error {
+	s.ExportUnpublished = includeUnpublished
+	for _, ar := range s.ExportResult {
+		if err := fn(ar); err != nil {
+			return err
+		}
+	}
+	return s.ExportErr
+}
+
+func (s *mockService) Delete(ctx context.Context, id string) error {
+	return s.DeleteErr
+}
+
+func (s *mockService) Transition(ctx context.Context, id string, t articles.Transition, version int) (articles.Article, error) {
+	s.TransitionInput = t
+	return s.TransitionResult, s.TransitionErr
+}
+
+func (s *mockService) Trash(ctx context.Context, limit, offset int) ([]articles.Article, error) {
+	return s.TrashResult, s.TrashErr
+}
+
+func (s *mockService) Restore(ctx context.Context, id string) (articles.Article, error) {
+	return s.RestoreResult, s.RestoreErr
+}
+
+func (s *mockService) Purge(ctx context.Context, id string) error {
+	return s.PurgeErr
+}
+
+func (s *mockService) Search(ctx context.Context, p articles.SearchParams) (articles.Articles, error) {
+	s.SearchParams = p
+	return s.SearchResult, s.SearchErr
+}
+
+func (s *mockService) Revisions(ctx context.Context, id string, includeUnpublished bool, limit, offset int) (articles.Revisions, error) {
+	s.RevisionsUnpublished = includeUnpublished
+	return s.RevisionsResult, s.RevisionsErr
+}
+
+func (s *mockService) Revision(ctx context.Context, id string, includeUnpublished bool, revision int) (articles.Revision, error) {
+	s.RevisionsUnpublished = includeUnpublished
+	return s.RevisionResult, s.RevisionErr
+}
+
+func (s *mockService) Revert(ctx context.Context, id string, revision, version int) (articles.Article, error) {
+	return s.RevertResult, s.RevertErr
+}
+
+func (s *mockService) Diff(ctx context.Context, id string, includeUnpublished bool, from, to int, unit articles.DiffUnit) (articles.Diff, error) {
+	s.RevisionsUnpublished = includeUnpublished
+	return s.DiffResult, s.DiffErr
+}
+
+func (s *mockService) Tags(ctx context.Context, limit, offset int) (articles.Tags, error) {
+	return s.TagsResult, s.TagsErr
+}
+
+type mockAuthorService struct {
+	GetResult       authors.Author
+	GetErr          error
+	SummariesResult map[string]authors.Summary
+	SummariesErr    error
+}
+
+func (s *mockAuthorService) Get(ctx context.Context, id string) (authors.Author, error) {
+	return s.GetResult, s.GetErr
+}
+
+func (s *mockAuthorService) GetAll(ctx context.Context, limit, offset int) ([]authors.Author, error) {
+	return nil, nil
+}
+
+func (s *mockAuthorService) Summaries(ctx context.Context, ids []string) (map[string]authors.Summary, error) {
+	return s.SummariesResult, s.SummariesErr
+}
+
+func (s *mockAuthorService) Create(ctx context.Context, au authors.AuthorCreateUpdate) (authors.Author, error) {
+	return authors.Author{}, nil
+}
+
+func (s *mockAuthorService) Update(ctx context.Context, au authors.AuthorCreateUpdate, id string) (authors.Author, error) {
+	return authors.Author{}, nil
+}
+
+func (s *mockAuthorService) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
 func TestHandlerGet(t *testing.T) {
 	id := uuid.New().String()
 	tests := map[string]struct {
@@ -53,10 +238,11 @@ func TestHandlerGet(t *testing.T) {
 		uri         string
 		response    interface{}
 		status      int
+		noETag      bool
 	}{
 		"Happy path": {
 			mockService: &mockService{
-				GetResult: articles.Article{ID: id},
+				GetResult: articles.Article{ID: id, Version: 1, Status: articles.StatusPublished},
 				GetErr:    nil,
 			},
 			uri: fmt.Sprintf("/articles/%s", id),
@@ -67,6 +253,8 @@ func TestHandlerGet(t *testing.T) {
 				CreatedAt:  time.Time{},
 				UpdatedAt:  time.Time{},
 				DisabledAt: nil,
+				Version:    1,
+				Status:     articles.StatusPublished,
 			},
 			status: http.StatusOK,
 		},
@@ -83,6 +271,127 @@ func TestHandlerGet(t *testing.T) {
 			},
 			status: http.StatusNotFound,
 		},
+		"As of": {
+			mockService: &mockService{
+				GetErr:        articles.ErrArticleNotFound,
+				GetAsOfResult: articles.Article{ID: id, Version: 1, Status: articles.StatusPublished},
+			},
+			uri:      fmt.Sprintf("/articles/%s?asOf=2021-04-01T12:00:00Z", id),
+			response: articles.Article{ID: id, Version: 1, Status: articles.StatusPublished},
+			status:   http.StatusOK,
+			noETag:   true,
+		},
+		"Unpublished": {
+			mockService: &mockService{
+				GetResult: articles.Article{ID: id, Version: 1, Status: articles.StatusDraft},
+			},
+			uri: fmt.Sprintf("/articles/%s", id),
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrArticleNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+		"Unpublished included": {
+			mockService: &mockService{
+				GetResult: articles.Article{ID: id, Version: 1, Status: articles.StatusDraft},
+			},
+			uri:      fmt.Sprintf("/articles/%s?includeUnpublished=true", id),
+			response: articles.Article{ID: id, Version: 1, Status: articles.StatusDraft},
+			status:   http.StatusOK,
+		},
+		"Unpublished as of": {
+			mockService: &mockService{
+				GetResult:     articles.Article{ID: id, Version: 2, Status: articles.StatusPublished},
+				GetAsOfResult: articles.Article{ID: id, Version: 1, Status: articles.StatusInReview},
+			},
+			uri: fmt.Sprintf("/articles/%s?asOf=2021-04-01T12:00:00Z", id),
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrArticleNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+		"Not found as of": {
+			mockService: &mockService{
+				GetResult:  articles.Article{ID: id, Version: 1, Status: articles.StatusPublished},
+				GetAsOfErr: articles.ErrArticleNotFound,
+			},
+			uri: fmt.Sprintf("/articles/%s?asOf=2021-04-01T12:00:00%%2B02:00", id),
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrArticleNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+		"By slug": {
+			mockService: &mockService{
+				GetResult:       articles.Article{ID: id, Slug: "some-title", Version: 1, Status: articles.StatusPublished},
+				ResolveSlugID:   id,
+				ResolveSlugSlug: "some-title",
+			},
+			uri:      "/articles/some-title",
+			response: articles.Article{ID: id, Slug: "some-title", Version: 1, Status: articles.StatusPublished},
+			status:   http.StatusOK,
+		},
+		"Unknown slug": {
+			mockService: &mockService{
+				GetResult:      articles.Article{ID: id, Version: 1, Status: articles.StatusPublished},
+				ResolveSlugErr: articles.ErrArticleNotFound,
+			},
+			uri: "/articles/some-title",
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrArticleNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+		"HTML": {
+			mockService: &mockService{
+				GetResult:  articles.Article{ID: id, Body: "*hi*", Version: 1, Status: articles.StatusPublished},
+				RenderHTML: "<p><em>hi</em></p>\n",
+			},
+			uri:      fmt.Sprintf("/articles/%s?format=html", id),
+			response: articles.Article{ID: id, Body: "*hi*", BodyHTML: "<p><em>hi</em></p>\n", Version: 1, Status: articles.StatusPublished},
+			status:   http.StatusOK,
+		},
+		"Unknown format": {
+			mockService: &mockService{},
+			uri:         fmt.Sprintf("/articles/%s?format=pdf", id),
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: `unknown format "pdf"`,
+				Field:       "format",
+			},
+			status: http.StatusBadRequest,
+		},
+		"Render error": {
+			mockService: &mockService{
+				GetResult: articles.Article{ID: id, Version: 1, Status: articles.StatusPublished},
+				RenderErr: articles.ErrArticleRender,
+			},
+			uri: fmt.Sprintf("/articles/%s?format=html", id),
+			response: errors.AppError{
+				Code:        errors.InternalServerError,
+				Description: articles.ErrArticleRender.Error(),
+				Field:       "format",
+			},
+			status: http.StatusInternalServerError,
+		},
+		"Malformed as of": {
+			mockService: &mockService{},
+			uri:         fmt.Sprintf("/articles/%s?asOf=yesterday", id),
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: "asOf must be an RFC3339 timestamp",
+				Field:       "asOf",
+			},
+			status: http.StatusBadRequest,
+		},
 		"Server error": {
 			mockService: &mockService{
 				GetResult: articles.Article{},
@@ -102,7 +411,7 @@ func TestHandlerGet(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			response := httptest.NewRecorder()
 			router := gin.New()
-			newHandler(router, test.mockService)
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
 
 			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
 			require.NoError(t, err)
@@ -118,6 +427,11 @@ func TestHandlerGet(t *testing.T) {
 					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
 				}
 				assert.Equal(t, test.response, ar)
+				if test.noETag {
+					assert.Empty(t, response.Header().Get("ETag"))
+				} else {
+					assert.Equal(t, `"1-json"`, response.Header().Get("ETag"))
+				}
 			} else {
 				var err errors.AppError
 				if err := json.Unmarshal(response.Body.Bytes(), &err); err != nil {
@@ -129,6 +443,217 @@ func TestHandlerGet(t *testing.T) {
 	}
 }
 
+func TestHandlerGetOldSlug(t *testing.T) {
+	response := httptest.NewRecorder()
+	router := gin.New()
+	newHandler(router, &mockService{ResolveSlugID: uuid.New().String(), ResolveSlugSlug: "new-title"}, &mockAuthorService{}, Config{})
+
+	req, err := http.NewRequest(http.MethodGet, "/articles/old-title?expand=author", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(response, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, response.Code)
+	assert.Equal(t, "/articles/new-title?expand=author", response.Header().Get("Location"))
+}
+
+func TestHandlerNegotiate(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		uri         string
+		accept      string
+		status      int
+		contentType string
+		body        string
+	}{
+		"XML": {
+			uri:         "/articles/" + id,
+			accept:      "application/xml",
+			status:      http.StatusOK,
+			contentType: "application/xml; charset=utf-8",
+			body:        "<id>" + id + "</id><slug>title</slug><title>title</title>",
+		},
+		"XML error": {
+			uri:         "/articles/not-a-slug",
+			accept:      "text/xml",
+			status:      http.StatusNotFound,
+			contentType: "application/xml; charset=utf-8",
+			body:        "<code>NOT_FOUND</code>",
+		},
+		"Not acceptable": {
+			uri:         "/articles/" + id,
+			accept:      "text/html",
+			status:      http.StatusNotAcceptable,
+			contentType: "application/json; charset=utf-8",
+			body:        `"code":"NOT_ACCEPTABLE"`,
+		},
+		"Export as CSV": {
+			uri:         "/articles/export?format=csv",
+			accept:      "text/csv",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			body:        id + ",title,title,",
+		},
+		"Export in no accepted format": {
+			uri:         "/articles/export",
+			accept:      "application/xml",
+			status:      http.StatusNotAcceptable,
+			contentType: "application/xml; charset=utf-8",
+			body:        "<field>Accept</field>",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ar := articles.Article{ID: id, Slug: "title", Title: "title", Version: 1, Status: articles.StatusPublished}
+			response := httptest.NewRecorder()
+			router := gin.New()
+			router.Use(middleware.Negotiate(ExportContentTypes))
+			newHandler(router, &mockService{
+				GetResult:      ar,
+				ExportResult:   []articles.Article{ar},
+				ResolveSlugErr: articles.ErrArticleNotFound,
+			}, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", test.accept)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+			assert.Equal(t, test.contentType, response.Header().Get("Content-Type"))
+			assert.Contains(t, response.Body.String(), test.body)
+		})
+	}
+}
+
+func TestHandlerGetAll(t *testing.T) {
+	id := uuid.New().String()
+	page := articles.Articles{
+		Articles: []articles.Article{{ID: id}},
+		PageInfo: articles.PageInfo{NextCursor: articles.EncodeCursor(articles.Cursor{Sort: "createdAt", Values: []string{"2021-04-01T12:00:00Z"}, ID: id})},
+	}
+
+	tests := map[string]struct {
+		uri      string
+		response interface{}
+		status   int
+	}{
+		"Happy path": {
+			uri:      "/articles/",
+			response: page,
+			status:   http.StatusOK,
+		},
+		"Next page": {
+			uri:      fmt.Sprintf("/articles/?cursor=%s&limit=500", page.NextCursor),
+			response: page,
+			status:   http.StatusOK,
+		},
+		"Negative paging": {
+			uri: "/articles/?limit=-1&offset=-5",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "limit must be a positive number", Field: "limit"},
+				{Code: errors.BadRequest, Description: "offset must not be negative", Field: "offset"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Malformed cursor": {
+			uri: "/articles/?cursor=not-a-cursor",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: articles.ErrInvalidCursor.Error(), Field: "cursor"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Sorted": {
+			uri:      "/articles/?sort=-updatedAt,%20title",
+			response: page,
+			status:   http.StatusOK,
+		},
+		"Unknown sort field": {
+			uri: "/articles/?sort=-updatedAt,body",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: `cannot sort by "body"`, Field: "sort"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Cursor for another sort": {
+			uri: fmt.Sprintf("/articles/?cursor=%s&sort=title", page.NextCursor),
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: articles.ErrInvalidCursor.Error(), Field: "cursor"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"As of": {
+			uri:      "/articles/?asOf=2021-04-01T12:00:00Z",
+			response: page,
+			status:   http.StatusOK,
+		},
+		"Malformed as of": {
+			uri: "/articles/?asOf=2021-04-01",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "asOf must be an RFC3339 timestamp", Field: "asOf"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Tagged": {
+			uri:      "/articles/?tag=go&tag=db&tagMatch=all",
+			response: page,
+			status:   http.StatusOK,
+		},
+		"Unknown tag match": {
+			uri: "/articles/?tag=go&tagMatch=some",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: `unknown tag match "some"`, Field: "tagMatch"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Unknown total": {
+			uri: "/articles/?total=some",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: `unknown total "some"`, Field: "total"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Cursor with offset": {
+			uri: fmt.Sprintf("/articles/?cursor=%s&offset=10", page.NextCursor),
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "offset cannot be combined with cursor", Field: "offset"},
+			}},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, &mockService{GetAllResult: page}, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusOK {
+				var al articles.Articles
+				if err := json.Unmarshal(response.Body.Bytes(), &al); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, al)
+			} else {
+				var errs errors.AppErrors
+				if err := json.Unmarshal(response.Body.Bytes(), &errs); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, errs)
+			}
+		})
+	}
+}
+
 func TestHandlerCreate(t *testing.T) {
 	id := uuid.New().String()
 	title := "some-title"
@@ -201,7 +726,7 @@ func TestHandlerCreate(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			response := httptest.NewRecorder()
 			router := gin.New()
-			newHandler(router, test.mockService)
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
 
 			req, err := http.NewRequest(http.MethodPost, test.uri, strings.NewReader(test.body))
 			require.NoError(t, err)
@@ -227,3 +752,1300 @@ func TestHandlerCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerUpdate(t *testing.T) {
+	id := uuid.New().String()
+	body := `{"title": "some-title", "body": "some-body"}`
+
+	tests := map[string]struct {
+		mockService articles.Service
+		ifMatch     string
+		response    interface{}
+		status      int
+		etag        string
+	}{
+		"Happy path": {
+			mockService: &mockService{UpdateResult: articles.Article{ID: id, Version: 4}},
+			ifMatch:     `"3-json"`,
+			response:    articles.Article{ID: id, Version: 4},
+			status:      http.StatusOK,
+			etag:        `"4-json"`,
+		},
+		"Unconditional": {
+			mockService: &mockService{UpdateResult: articles.Article{ID: id, Version: 2}},
+			response:    articles.Article{ID: id, Version: 2},
+			status:      http.StatusOK,
+			etag:        `"2-json"`,
+		},
+		"Stale version": {
+			mockService: &mockService{UpdateErr: articles.ErrArticleVersionMismatch},
+			ifMatch:     `"2-json"`,
+			response: errors.AppError{
+				Code:        errors.PreconditionFailed,
+				Description: articles.ErrArticleVersionMismatch.Error(),
+				Field:       "If-Match",
+			},
+			status: http.StatusPreconditionFailed,
+		},
+		"Weak tag never matches": {
+			mockService: &mockService{UpdateResult: articles.Article{ID: id, Version: 4}},
+			ifMatch:     `W/"3-json"`,
+			response: errors.AppError{
+				Code:        errors.PreconditionFailed,
+				Description: articles.ErrArticleVersionMismatch.Error(),
+				Field:       "If-Match",
+			},
+			status: http.StatusPreconditionFailed,
+		},
+		"Listed version": {
+			mockService: &mockService{GetResult: articles.Article{ID: id, Version: 3}, UpdateResult: articles.Article{ID: id, Version: 4}},
+			ifMatch:     `"2-json", W/"1-json", "3-xml"`,
+			response:    articles.Article{ID: id, Version: 4},
+			status:      http.StatusOK,
+			etag:        `"4-json"`,
+		},
+		"Version not listed": {
+			mockService: &mockService{GetResult: articles.Article{ID: id, Version: 3}, UpdateResult: articles.Article{ID: id, Version: 4}},
+			ifMatch:     `"1-json", "2"`,
+			response: errors.AppError{
+				Code:        errors.PreconditionFailed,
+				Description: articles.ErrArticleVersionMismatch.Error(),
+				Field:       "If-Match",
+			},
+			status: http.StatusPreconditionFailed,
+		},
+		"Any version": {
+			mockService: &mockService{GetResult: articles.Article{ID: id, Version: 3}, UpdateResult: articles.Article{ID: id, Version: 4}},
+			ifMatch:     `*`,
+			response:    articles.Article{ID: id, Version: 4},
+			status:      http.StatusOK,
+			etag:        `"4-json"`,
+		},
+		"Any version of a missing article": {
+			mockService: &mockService{GetErr: articles.ErrArticleNotFound, UpdateErr: articles.ErrArticleNotFound},
+			ifMatch:     `*`,
+			response: errors.AppError{
+				Code:        errors.PreconditionFailed,
+				Description: articles.ErrArticleVersionMismatch.Error(),
+				Field:       "If-Match",
+			},
+			status: http.StatusPreconditionFailed,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/articles/%s", id), strings.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Add("Content-Type", "application/json")
+			if test.ifMatch != "" {
+				req.Header.Add("If-Match", test.ifMatch)
+			}
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+			assert.Equal(t, test.etag, response.Header().Get("ETag"))
+
+			if test.status == http.StatusOK {
+				var ar articles.Article
+				if err := json.Unmarshal(response.Body.Bytes(), &ar); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, ar)
+			} else {
+				var err errors.AppError
+				if err := json.Unmarshal(response.Body.Bytes(), &err); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerPatch(t *testing.T) {
+	id := uuid.New().String()
+
+	tests := map[string]struct {
+		mockService articles.Service
+		contentType string
+		body        string
+		response    interface{}
+		status      int
+	}{
+		"Happy path": {
+			mockService: &mockService{PatchResult: articles.Article{ID: id, Title: "new-title", Version: 2}},
+			contentType: "application/merge-patch+json",
+			body:        `{"title": "new-title"}`,
+			response:    articles.Article{ID: id, Title: "new-title", Version: 2},
+			status:      http.StatusOK,
+		},
+		"Tags": {
+			mockService: &mockService{PatchResult: articles.Article{ID: id, Version: 2, Tags: []string{"go"}}},
+			contentType: "application/merge-patch+json",
+			body:        `{"tags": ["Go"]}`,
+			response:    articles.Article{ID: id, Version: 2, Tags: []string{"go"}},
+			status:      http.StatusOK,
+		},
+		"Invalid tags": {
+			mockService: &mockService{},
+			contentType: "application/merge-patch+json",
+			body:        `{"tags": "go"}`,
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: "tags must be an array of strings",
+				Field:       "tags",
+			},
+			status: http.StatusBadRequest,
+		},
+		"Invalid schedule": {
+			mockService: &mockService{},
+			contentType: "application/merge-patch+json",
+			body:        `{"publishAt": "tomorrow"}`,
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: "field must be an RFC3339 timestamp or null",
+				Field:       "publishAt",
+			},
+			status: http.StatusBadRequest,
+		},
+		"Expiring before publishing": {
+			mockService: &mockService{PatchErr: articles.ErrInvalidSchedule},
+			contentType: "application/merge-patch+json",
+			body:        `{"publishAt": "2021-04-02T12:00:00Z", "expireAt": "2021-04-01T12:00:00Z"}`,
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: articles.ErrInvalidSchedule.Error(),
+				Field:       "expireAt",
+			},
+			status: http.StatusBadRequest,
+		},
+		"Removing a required field": {
+			mockService: &mockService{},
+			contentType: "application/merge-patch+json",
+			body:        `{"title": null}`,
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: "required field cannot be removed",
+				Field:       "title",
+			},
+			status: http.StatusBadRequest,
+		},
+		"Unknown field": {
+			mockService: &mockService{},
+			contentType: "application/merge-patch+json",
+			body:        `{"id": "other-id"}`,
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: "field cannot be patched",
+				Field:       "id",
+			},
+			status: http.StatusBadRequest,
+		},
+		"Not an object": {
+			mockService: &mockService{},
+			contentType: "application/merge-patch+json",
+			body:        `["title"]`,
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: "merge patch must be a JSON object",
+				Field:       "",
+			},
+			status: http.StatusBadRequest,
+		},
+		"Unsupported content type": {
+			mockService: &mockService{},
+			contentType: "application/json-patch+json",
+			body:        `[{"op": "remove", "path": "/title"}]`,
+			response: errors.AppError{
+				Code:        errors.UnsupportedMediaType,
+				Description: errors.Descriptions[errors.UnsupportedMediaType],
+				Field:       "Content-Type",
+			},
+			status: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/articles/%s", id), strings.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Add("Content-Type", test.contentType)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusOK {
+				var ar articles.Article
+				if err := json.Unmarshal(response.Body.Bytes(), &ar); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, ar)
+			} else {
+				var err errors.AppError
+				if err := json.Unmarshal(response.Body.Bytes(), &err); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerDelete(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		mockService articles.Service
+		uri         string
+		response    interface{}
+		status      int
+	}{
+		"Happy path": {
+			mockService: &mockService{DeleteErr: nil},
+			uri:         fmt.Sprintf("/articles/%s", id),
+			status:      http.StatusNoContent,
+		},
+		"Already disabled": {
+			mockService: &mockService{DeleteErr: articles.ErrArticleNotFound},
+			uri:         fmt.Sprintf("/articles/%s", id),
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrArticleNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+		"Permanent delete": {
+			mockService: &mockService{DeleteErr: articles.ErrArticleDelete, PurgeErr: nil},
+			uri:         fmt.Sprintf("/articles/%s?permanent=true", id),
+			status:      http.StatusNoContent,
+		},
+		"Permanent delete of live article": {
+			mockService: &mockService{PurgeErr: articles.ErrArticleNotDisabled},
+			uri:         fmt.Sprintf("/articles/%s?permanent=true", id),
+			response: errors.AppError{
+				Code:        errors.ForbiddenAction,
+				Description: articles.ErrArticleNotDisabled.Error(),
+				Field:       "id",
+			},
+			status: http.StatusForbidden,
+		},
+		"Delete failure": {
+			mockService: &mockService{DeleteErr: articles.ErrArticleDelete},
+			uri:         fmt.Sprintf("/articles/%s", id),
+			response: errors.AppError{
+				Code:        errors.InternalServerError,
+				Description: "unable to delete article",
+				Field:       "",
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodDelete, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusNoContent {
+				assert.Empty(t, response.Body.String())
+			} else {
+				var err errors.AppError
+				if err := json.Unmarshal(response.Body.Bytes(), &err); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerRestore(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		mockService articles.Service
+		uri         string
+		response    interface{}
+		status      int
+	}{
+		"Happy path": {
+			mockService: &mockService{RestoreResult: articles.Article{ID: id}},
+			uri:         fmt.Sprintf("/articles/%s/restore", id),
+			response:    articles.Article{ID: id},
+			status:      http.StatusOK,
+		},
+		"Not in trash": {
+			mockService: &mockService{RestoreErr: articles.ErrArticleNotDisabled},
+			uri:         fmt.Sprintf("/articles/%s/restore", id),
+			response: errors.AppError{
+				Code:        errors.ForbiddenAction,
+				Description: articles.ErrArticleNotDisabled.Error(),
+				Field:       "id",
+			},
+			status: http.StatusForbidden,
+		},
+		"Not found": {
+			mockService: &mockService{RestoreErr: articles.ErrArticleNotFound},
+			uri:         fmt.Sprintf("/articles/%s/restore", id),
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrArticleNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodPost, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusOK {
+				var ar articles.Article
+				if err := json.Unmarshal(response.Body.Bytes(), &ar); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, ar)
+			} else {
+				var err errors.AppError
+				if err := json.Unmarshal(response.Body.Bytes(), &err); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerTrash(t *testing.T) {
+	id := uuid.New().String()
+	now := time.Now().UTC()
+
+	response := httptest.NewRecorder()
+	router := gin.New()
+	newHandler(router, &mockService{
+		GetErr:      articles.ErrArticleNotFound,
+		TrashResult: []articles.Article{{ID: id, DisabledAt: &now}},
+	}, &mockAuthorService{}, Config{})
+
+	req, err := http.NewRequest(http.MethodGet, "/articles/trash", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(response, req)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	var al articles.Articles
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &al))
+	require.Len(t, al.Articles, 1)
+	assert.Equal(t, id, al.Articles[0].ID)
+}
+
+func TestHandlerGetNotModified(t *testing.T) {
+	id := uuid.New().String()
+	updated := time.Date(2021, 4, 1, 12, 0, 0, 500, time.UTC)
+	ar := articles.Article{ID: id, Version: 3, UpdatedAt: updated, Status: articles.StatusPublished}
+	removed := articles.Article{ID: uuid.New().String(), Version: 2, UpdatedAt: updated.Add(-time.Minute)}
+	older := articles.Article{ID: uuid.New().String(), Version: 1, UpdatedAt: updated.Add(-time.Hour)}
+	page := articles.Articles{Articles: []articles.Article{ar}}
+
+	tests := map[string]struct {
+		uri          string
+		page         articles.Articles
+		headers      map[string]string
+		status       int
+		lastModified string
+	}{
+		"Matching If-None-Match": {
+			uri:          fmt.Sprintf("/articles/%s", id),
+			headers:      map[string]string{"If-None-Match": `"2-json", "3-json"`},
+			status:       http.StatusNotModified,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"Weak If-None-Match": {
+			uri:          fmt.Sprintf("/articles/%s", id),
+			headers:      map[string]string{"If-None-Match": `W/"3-json"`},
+			status:       http.StatusNotModified,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"Stale If-None-Match": {
+			uri:          fmt.Sprintf("/articles/%s", id),
+			headers:      map[string]string{"If-None-Match": `"2-json"`},
+			status:       http.StatusOK,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"Not modified since": {
+			uri:          fmt.Sprintf("/articles/%s", id),
+			headers:      map[string]string{"If-Modified-Since": updated.Format(http.TimeFormat)},
+			status:       http.StatusNotModified,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"Modified since": {
+			uri:          fmt.Sprintf("/articles/%s", id),
+			headers:      map[string]string{"If-Modified-Since": updated.Add(-time.Minute).Format(http.TimeFormat)},
+			status:       http.StatusOK,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"If-None-Match takes precedence": {
+			uri: fmt.Sprintf("/articles/%s", id),
+			headers: map[string]string{
+				"If-None-Match":     `"2-json"`,
+				"If-Modified-Since": updated.Format(http.TimeFormat),
+			},
+			status:       http.StatusOK,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"Matching list": {
+			uri:          "/articles/",
+			page:         page,
+			headers:      map[string]string{"If-None-Match": listETag(page, negotiate.MIMEJSON)},
+			status:       http.StatusNotModified,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"Stale list": {
+			uri:          "/articles/",
+			page:         page,
+			headers:      map[string]string{"If-None-Match": listETag(articles.Articles{Articles: []articles.Article{{ID: id, Version: 2}}}, negotiate.MIMEJSON)},
+			status:       http.StatusOK,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"List not modified since": {
+			uri:          "/articles/",
+			page:         articles.Articles{Articles: []articles.Article{older, ar}},
+			headers:      map[string]string{"If-Modified-Since": updated.Format(http.TimeFormat)},
+			status:       http.StatusNotModified,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"List modified since": {
+			uri:          "/articles/",
+			page:         page,
+			headers:      map[string]string{"If-Modified-Since": updated.Add(-time.Minute).Format(http.TimeFormat)},
+			status:       http.StatusOK,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"Article removed from the list": {
+			uri:          "/articles/",
+			page:         articles.Articles{Articles: []articles.Article{ar, older}},
+			headers:      map[string]string{"If-None-Match": listETag(articles.Articles{Articles: []articles.Article{ar, removed}}, negotiate.MIMEJSON)},
+			status:       http.StatusOK,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"Older article moved into the list": {
+			uri:  "/articles/",
+			page: articles.Articles{Articles: []articles.Article{ar, older}},
+			headers: map[string]string{
+				"If-None-Match":     listETag(articles.Articles{Articles: []articles.Article{ar, removed}}, negotiate.MIMEJSON),
+				"If-Modified-Since": updated.Format(http.TimeFormat),
+			},
+			status:       http.StatusOK,
+			lastModified: updated.Format(http.TimeFormat),
+		},
+		"Empty list": {
+			uri:     "/articles/",
+			page:    articles.Articles{Articles: []articles.Article{}},
+			headers: map[string]string{"If-Modified-Since": updated.Format(http.TimeFormat)},
+			status:  http.StatusOK,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, &mockService{GetResult: ar, GetAllResult: test.page}, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+			for k, v := range test.headers {
+				req.Header.Add(k, v)
+			}
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+			assert.NotEmpty(t, response.Header().Get("ETag"))
+			assert.Equal(t, test.lastModified, response.Header().Get("Last-Modified"))
+			if test.status == http.StatusNotModified {
+				assert.Empty(t, response.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerETagPerFormat(t *testing.T) {
+	id := uuid.New().String()
+	ar := articles.Article{ID: id, Version: 3, Status: articles.StatusPublished}
+
+	tests := map[string]struct {
+		accept      string
+		ifNoneMatch string
+		etag        string
+		status      int
+	}{
+		"JSON":                  {accept: "application/json", etag: `"3-json"`, status: http.StatusOK},
+		"XML":                   {accept: "application/xml", etag: `"3-xml"`, status: http.StatusOK},
+		"XML still current":     {accept: "application/xml", ifNoneMatch: `"3-xml"`, etag: `"3-xml"`, status: http.StatusNotModified},
+		"JSON tag held for XML": {accept: "application/xml", ifNoneMatch: `"3-json"`, etag: `"3-xml"`, status: http.StatusOK},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			router.Use(middleware.Negotiate())
+			newHandler(router, &mockService{GetResult: ar}, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, "/articles/"+id, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", test.accept)
+			if test.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", test.ifNoneMatch)
+			}
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+			assert.Equal(t, test.etag, response.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestHandlerAsOfNotConditional(t *testing.T) {
+	id := uuid.New().String()
+	ar := articles.Article{ID: id, Version: 3, UpdatedAt: time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC), Status: articles.StatusPublished}
+	svc := &mockService{GetAsOfResult: ar, GetAllResult: articles.Articles{Articles: []articles.Article{ar}}}
+
+	tests := map[string]struct {
+		uri     string
+		headers map[string]string
+	}{
+		"Article": {
+			uri:     fmt.Sprintf("/articles/%s?asOf=2021-04-02T12:00:00Z", id),
+			headers: map[string]string{"If-None-Match": etag(ar, negotiate.MIMEJSON), "If-Modified-Since": ar.UpdatedAt.Format(http.TimeFormat)},
+		},
+		"Listing": {
+			uri:     "/articles/?asOf=2021-04-02T12:00:00Z",
+			headers: map[string]string{"If-None-Match": listETag(svc.GetAllResult, negotiate.MIMEJSON)},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, svc, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+			for k, v := range test.headers {
+				req.Header.Add(k, v)
+			}
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.Empty(t, response.Header().Get("ETag"))
+			assert.Empty(t, response.Header().Get("Last-Modified"))
+		})
+	}
+}
+
+func TestHandlerSearch(t *testing.T) {
+	id := uuid.New().String()
+	score := 0.5
+	results := articles.Articles{
+		Articles: []articles.Article{{ID: id, Title: "some-title", Score: &score}},
+		PageInfo: articles.PageInfo{Limit: 25},
+	}
+
+	tests := map[string]struct {
+		mockService articles.Service
+		uri         string
+		response    interface{}
+		status      int
+	}{
+		"Happy path": {
+			mockService: &mockService{SearchResult: results},
+			uri:         "/articles/search?q=sme-titl&body=true",
+			response:    results,
+			status:      http.StatusOK,
+		},
+		"Missing query": {
+			mockService: &mockService{SearchResult: results},
+			uri:         "/articles/search?q=%20&limit=0",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "limit must be a positive number", Field: "limit"},
+				{Code: errors.BadRequest, Description: "search query is required", Field: "q"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Full-text search on listing": {
+			mockService: &mockService{SearchResult: results},
+			uri:         "/articles/?q=go%20database&limit=10",
+			response:    results,
+			status:      http.StatusOK,
+		},
+		"Sorted full-text search": {
+			mockService: &mockService{SearchResult: results},
+			uri:         "/articles/?q=go&sort=title",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "search results cannot be sorted", Field: "sort"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Unknown mode": {
+			mockService: &mockService{SearchResult: results},
+			uri:         "/articles/search?q=go&mode=regex",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: `unknown search mode "regex"`, Field: "mode"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Listing cursor": {
+			mockService: &mockService{SearchResult: results},
+			uri:         fmt.Sprintf("/articles/search?q=go&cursor=%s", articles.EncodeCursor(articles.Cursor{Sort: "createdAt", Values: []string{"2021-04-01T12:00:00Z"}, ID: id})),
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: articles.ErrInvalidCursor.Error(), Field: "cursor"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Unknown total": {
+			mockService: &mockService{SearchResult: results},
+			uri:         "/articles/search?q=go&total=sometimes",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: `unknown total "sometimes"`, Field: "total"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Search failure": {
+			mockService: &mockService{SearchErr: fmt.Errorf("internal")},
+			uri:         "/articles/search?q=title",
+			response: errors.AppError{
+				Code:        errors.InternalServerError,
+				Description: "internal",
+				Field:       "unknown",
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			switch expected := test.response.(type) {
+			case articles.Articles:
+				var sr articles.Articles
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &sr))
+				assert.Equal(t, expected, sr)
+			case errors.AppErrors:
+				var errs errors.AppErrors
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &errs))
+				assert.Equal(t, expected, errs)
+			default:
+				var appErr errors.AppError
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &appErr))
+				assert.Equal(t, expected, appErr)
+			}
+		})
+	}
+}
+
+func TestHandlerSearchPaging(t *testing.T) {
+	c := articles.Cursor{Sort: "rank", Values: []string{"0.25"}, ID: uuid.New().String()}
+	total := int64(42)
+	svc := &mockService{SearchResult: articles.Articles{
+		Articles: []articles.Article{},
+		PageInfo: articles.PageInfo{Total: &total, Limit: 10, NextCursor: "next", PrevCursor: "prev"},
+	}}
+
+	response := httptest.NewRecorder()
+	router := gin.New()
+	newHandler(router, svc, &mockAuthorService{}, Config{})
+
+	req, err := http.NewRequest(http.MethodGet, "/articles/?q=go&limit=10&total=exact&cursor="+articles.EncodeCursor(c), nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(response, req)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, articles.SearchParams{
+		Query:     "go",
+		Mode:      articles.SearchFullText,
+		Threshold: defaultSimilarityThreshold,
+		Limit:     10,
+		Cursor:    &c,
+		Total:     articles.TotalExact,
+	}, svc.SearchParams)
+	assert.Equal(t, `</articles/?limit=10&q=go&total=exact>; rel="first", </articles/?cursor=prev&limit=10&q=go&total=exact>; rel="prev", `+
+		`</articles/?cursor=next&limit=10&q=go&total=exact>; rel="next"`, response.Header().Get("Link"))
+	assert.JSONEq(t, `{"articles": [], "total": 42, "limit": 10, "nextCursor": "next", "prevCursor": "prev"}`, response.Body.String())
+}
+
+func TestHandlerRevisions(t *testing.T) {
+	id := uuid.New().String()
+	revs := articles.Revisions{Revisions: []articles.Revision{
+		{ArticleID: id, Revision: 1, Title: "title", Body: "body", RequestID: "req-1"},
+		{ArticleID: id, Revision: 2, Title: "title", Body: "new body", RequestID: "req-2"},
+	}}
+
+	response := httptest.NewRecorder()
+	router := gin.New()
+	newHandler(router, &mockService{RevisionsResult: revs}, &mockAuthorService{}, Config{})
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/articles/%s/revisions", id), nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(response, req)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	var result articles.Revisions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	assert.Equal(t, revs, result)
+}
+
+func TestHandlerRevisionsUnpublished(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		uri         string
+		unpublished bool
+	}{
+		"Revisions":          {uri: fmt.Sprintf("/articles/%s/revisions", id)},
+		"Revisions included": {uri: fmt.Sprintf("/articles/%s/revisions?includeUnpublished=true", id), unpublished: true},
+		"Revision":           {uri: fmt.Sprintf("/articles/%s/revisions/1", id)},
+		"Revision included":  {uri: fmt.Sprintf("/articles/%s/revisions/1?includeUnpublished=true", id), unpublished: true},
+		"Diff":               {uri: fmt.Sprintf("/articles/%s/diff?from=1&to=2", id)},
+		"Diff included":      {uri: fmt.Sprintf("/articles/%s/diff?from=1&to=2&includeUnpublished=true", id), unpublished: true},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			svc := &mockService{}
+
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, svc, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.Equal(t, test.unpublished, svc.RevisionsUnpublished)
+		})
+	}
+}
+
+func TestHandlerRevision(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		mockService articles.Service
+		uri         string
+		response    interface{}
+		status      int
+	}{
+		"Happy path": {
+			mockService: &mockService{RevisionResult: articles.Revision{ArticleID: id, Revision: 2}},
+			uri:         fmt.Sprintf("/articles/%s/revisions/2", id),
+			response:    articles.Revision{ArticleID: id, Revision: 2},
+			status:      http.StatusOK,
+		},
+		"Invalid revision": {
+			mockService: &mockService{},
+			uri:         fmt.Sprintf("/articles/%s/revisions/0", id),
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: "revision must be a positive number",
+				Field:       "rev",
+			},
+			status: http.StatusBadRequest,
+		},
+		"Revision not found": {
+			mockService: &mockService{RevisionErr: articles.ErrRevisionNotFound},
+			uri:         fmt.Sprintf("/articles/%s/revisions/9", id),
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrRevisionNotFound.Error(),
+				Field:       "rev",
+			},
+			status: http.StatusNotFound,
+		},
+		"Article not found": {
+			mockService: &mockService{RevisionErr: articles.ErrArticleNotFound},
+			uri:         fmt.Sprintf("/articles/%s/revisions/1", id),
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrArticleNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusOK {
+				var rev articles.Revision
+				if err := json.Unmarshal(response.Body.Bytes(), &rev); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, rev)
+			} else {
+				var err errors.AppError
+				if err := json.Unmarshal(response.Body.Bytes(), &err); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerRevert(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		mockService articles.Service
+		uri         string
+		ifMatch     string
+		response    interface{}
+		status      int
+	}{
+		"Happy path": {
+			mockService: &mockService{RevertResult: articles.Article{ID: id, Version: 5}},
+			uri:         fmt.Sprintf("/articles/%s/revisions/2/revert", id),
+			ifMatch:     `"4-json"`,
+			response:    articles.Article{ID: id, Version: 5},
+			status:      http.StatusOK,
+		},
+		"Invalid revision": {
+			mockService: &mockService{},
+			uri:         fmt.Sprintf("/articles/%s/revisions/latest/revert", id),
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: "revision must be a positive number",
+				Field:       "rev",
+			},
+			status: http.StatusBadRequest,
+		},
+		"Stale version": {
+			mockService: &mockService{RevertErr: articles.ErrArticleVersionMismatch},
+			uri:         fmt.Sprintf("/articles/%s/revisions/2/revert", id),
+			ifMatch:     `"3-json"`,
+			response: errors.AppError{
+				Code:        errors.PreconditionFailed,
+				Description: articles.ErrArticleVersionMismatch.Error(),
+				Field:       ifMatchHeader,
+			},
+			status: http.StatusPreconditionFailed,
+		},
+		"Revision not found": {
+			mockService: &mockService{RevertErr: articles.ErrRevisionNotFound},
+			uri:         fmt.Sprintf("/articles/%s/revisions/9/revert", id),
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrRevisionNotFound.Error(),
+				Field:       "rev",
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodPost, test.uri, nil)
+			require.NoError(t, err)
+			if test.ifMatch != "" {
+				req.Header.Set(ifMatchHeader, test.ifMatch)
+			}
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusOK {
+				var ar articles.Article
+				if err := json.Unmarshal(response.Body.Bytes(), &ar); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, ar)
+				assert.Equal(t, `"5-json"`, response.Header().Get(etagHeader))
+			} else {
+				var err errors.AppError
+				if err := json.Unmarshal(response.Body.Bytes(), &err); err != nil {
+					assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+				}
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerDiff(t *testing.T) {
+	id := uuid.New().String()
+	diff := articles.Diff{ArticleID: id, From: 1, To: 2, Body: "--- body@1\n+++ body@2\n@@ -1 +1 @@\n-a\n+b\n"}
+	tests := map[string]struct {
+		mockService articles.Service
+		uri         string
+		response    interface{}
+		status      int
+	}{
+		"Happy path": {
+			mockService: &mockService{DiffResult: diff},
+			uri:         fmt.Sprintf("/articles/%s/diff?from=1&to=2&unit=word", id),
+			response:    diff,
+			status:      http.StatusOK,
+		},
+		"Invalid parameters": {
+			mockService: &mockService{},
+			uri:         fmt.Sprintf("/articles/%s/diff?from=0&unit=char", id),
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "revision must be a positive number", Field: "from"},
+				{Code: errors.BadRequest, Description: "revision must be a positive number", Field: "to"},
+				{Code: errors.BadRequest, Description: `unknown diff unit "char"`, Field: "unit"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Article not found": {
+			mockService: &mockService{DiffErr: articles.ErrArticleNotFound},
+			uri:         fmt.Sprintf("/articles/%s/diff?from=1&to=2", id),
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrArticleNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusOK {
+				var d articles.Diff
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &d))
+				assert.Equal(t, test.response, d)
+			} else if test.status == http.StatusBadRequest {
+				var errs errors.AppErrors
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &errs))
+				assert.Equal(t, test.response, errs)
+			} else {
+				var err errors.AppError
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &err))
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerTags(t *testing.T) {
+	tags := articles.Tags{Tags: []articles.Tag{{Name: "go", Count: 3}, {Name: "db", Count: 1}}}
+
+	response := httptest.NewRecorder()
+	router := gin.New()
+	newHandler(router, &mockService{TagsResult: tags}, &mockAuthorService{}, Config{})
+
+	req, err := http.NewRequest(http.MethodGet, "/tags?limit=10", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(response, req)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	var result articles.Tags
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	assert.Equal(t, tags, result)
+}
+
+func TestHandlerExpandAuthor(t *testing.T) {
+	id := uuid.New().String()
+	authorID := uuid.New().String()
+	summary := authors.Summary{ID: authorID, Name: "some-author"}
+	aus := &mockAuthorService{SummariesResult: map[string]authors.Summary{authorID: summary}}
+
+	tests := map[string]struct {
+		mockService articles.Service
+		uri         string
+		response    interface{}
+		status      int
+	}{
+		"Get": {
+			mockService: &mockService{GetResult: articles.Article{ID: id, Version: 1, AuthorID: &authorID, Status: articles.StatusPublished}},
+			uri:         fmt.Sprintf("/articles/%s?expand=author", id),
+			response:    articles.Article{ID: id, Version: 1, AuthorID: &authorID, Author: &summary, Status: articles.StatusPublished},
+			status:      http.StatusOK,
+		},
+		"List": {
+			mockService: &mockService{GetAllResult: articles.Articles{Articles: []articles.Article{{ID: id, AuthorID: &authorID}, {ID: id}}}},
+			uri:         "/articles/?expand=author",
+			response:    articles.Articles{Articles: []articles.Article{{ID: id, AuthorID: &authorID, Author: &summary}, {ID: id}}},
+			status:      http.StatusOK,
+		},
+		"Unknown expansion": {
+			mockService: &mockService{},
+			uri:         fmt.Sprintf("/articles/%s?expand=author,comments", id),
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: `cannot expand "comments"`,
+				Field:       "expand",
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, aus, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			switch expect := test.response.(type) {
+			case articles.Article:
+				var ar articles.Article
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &ar))
+				assert.Equal(t, expect, ar)
+				assert.Empty(t, response.Header().Get(etagHeader))
+			case articles.Articles:
+				var page articles.Articles
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+				assert.Equal(t, expect, page)
+			default:
+				var err errors.AppError
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &err))
+				assert.Equal(t, expect, err)
+			}
+		})
+	}
+}
+
+func TestHandlerAuthorArticles(t *testing.T) {
+	id := uuid.New().String()
+	authorID := uuid.New().String()
+	page := articles.Articles{Articles: []articles.Article{{ID: id, AuthorID: &authorID}}}
+
+	tests := map[string]struct {
+		authorService authors.Service
+		response      interface{}
+		status        int
+	}{
+		"Happy path": {
+			authorService: &mockAuthorService{GetResult: authors.Author{ID: authorID}},
+			response:      page,
+			status:        http.StatusOK,
+		},
+		"Author not found": {
+			authorService: &mockAuthorService{GetErr: authors.ErrAuthorNotFound},
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: authors.ErrAuthorNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, &mockService{GetAllResult: page}, test.authorService, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/authors/%s/articles", authorID), nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusOK {
+				var result articles.Articles
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+				assert.Equal(t, test.response, result)
+			} else {
+				var err errors.AppError
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &err))
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerTransition(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		mockService *mockService
+		uri         string
+		transition  articles.Transition
+		response    interface{}
+		status      int
+	}{
+		"Submit": {
+			mockService: &mockService{TransitionResult: articles.Article{ID: id, Status: articles.StatusInReview, Version: 2}},
+			uri:         fmt.Sprintf("/articles/%s/submit", id),
+			transition:  articles.Transitions["submit"],
+			response:    articles.Article{ID: id, Status: articles.StatusInReview, Version: 2},
+			status:      http.StatusOK,
+		},
+		"Publish": {
+			mockService: &mockService{TransitionResult: articles.Article{ID: id, Status: articles.StatusPublished, Version: 3}},
+			uri:         fmt.Sprintf("/articles/%s/publish", id),
+			transition:  articles.Transitions["publish"],
+			response:    articles.Article{ID: id, Status: articles.StatusPublished, Version: 3},
+			status:      http.StatusOK,
+		},
+		"Invalid transition": {
+			mockService: &mockService{TransitionErr: articles.ErrInvalidTransition},
+			uri:         fmt.Sprintf("/articles/%s/archive", id),
+			transition:  articles.Transitions["archive"],
+			response: errors.AppError{
+				Code:        errors.ForbiddenAction,
+				Description: articles.ErrInvalidTransition.Error(),
+				Field:       "status",
+			},
+			status: http.StatusForbidden,
+		},
+		"Not found": {
+			mockService: &mockService{TransitionErr: articles.ErrArticleNotFound},
+			uri:         fmt.Sprintf("/articles/%s/reject", id),
+			transition:  articles.Transitions["reject"],
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: articles.ErrArticleNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodPost, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+			assert.Equal(t, test.transition, test.mockService.TransitionInput)
+
+			if test.status == http.StatusOK {
+				var ar articles.Article
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &ar))
+				assert.Equal(t, test.response, ar)
+			} else {
+				var err errors.AppError
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &err))
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerGetAllStatuses(t *testing.T) {
+	tests := map[string]struct {
+		uri      string
+		statuses []articles.Status
+		status   int
+	}{
+		"Published by default": {
+			uri:      "/articles/",
+			statuses: []articles.Status{articles.StatusPublished},
+			status:   http.StatusOK,
+		},
+		"Requested statuses": {
+			uri:      "/articles/?status=draft&status=in_review",
+			statuses: []articles.Status{articles.StatusDraft, articles.StatusInReview},
+			status:   http.StatusOK,
+		},
+		"Unknown status": {
+			uri:    "/articles/?status=deleted",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := &mockService{GetAllResult: articles.Articles{Articles: []articles.Article{}}}
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, service, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+			assert.Equal(t, test.statuses, service.GetAllParams.Statuses)
+		})
+	}
+}
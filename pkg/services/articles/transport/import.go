@@ -0,0 +1,305 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/context"
+	"github.com/kott/go-service-example/pkg/utils/log"
+	"github.com/kott/go-service-example/pkg/utils/middleware"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
+)
+
+const (
+	importPath = "/articles/import"
+
+	ndjsonContentType = "application/x-ndjson"
+	csvContentType    = "text/csv"
+
+	// maxImportLineSize is the longest line of an NDJSON import, which bounds the memory used per line
+	maxImportLineSize = 8 << 20
+
+	// maxImportErrors is the number of failed lines reported in the response, any further ones are only counted
+	maxImportErrors = 1000
+
+	// csvTagSeparator separates the tags within the tags column of a CSV import
+	csvTagSeparator = ";"
+)
+
+// ImportContentTypes lets the import route accept its streamed content types past middleware.ForceJSON
+var ImportContentTypes = middleware.RouteContentTypes{
+	Path:         importPath,
+	ContentTypes: []string{ndjsonContentType, csvContentType},
+}
+
+// importResponse holds how far the import got and an error for each line which was not imported
+type importResponse struct {
+	articles.ImportProgress
+	errors.AppErrors
+}
+
+// Import creates articles from a streamed NDJSON body, one article per line, or a CSV body with a header naming
+// the columns. The body is read and imported in batches as it arrives, so uploads of any size can be imported.
+// Lines which cannot be imported are skipped and reported with their line number in the Field of the error.
+// Progress is logged after every batch; the response is only written once the whole body has been read.
+func (h *handler) Import(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+
+	var r articles.ImportReader
+	switch c.ContentType() {
+	case ndjsonContentType:
+		r = newNDJSONReader(c.Request.Body)
+	case csvContentType:
+		cr, appErr := newCSVReader(c.Request.Body)
+		if appErr != nil {
+			log.Info(ctx, "invalid csv header: %s", appErr.Description)
+			negotiate.Render(c, http.StatusBadRequest, appErr)
+			return
+		}
+		r = cr
+	default:
+		negotiate.Render(c, http.StatusUnsupportedMediaType, errors.NewAppError(errors.UnsupportedMediaType,
+			fmt.Sprintf("articles can be imported from %s or %s", ndjsonContentType, csvContentType), "Content-Type"))
+		return
+	}
+
+	response := importResponse{AppErrors: errors.AppErrors{Errors: make([]errors.AppError, 0)}}
+	progress, err := h.ArticleService.Import(ctx, r, func(p articles.ImportProgress, failures []articles.ImportFailure) {
+		log.Info(ctx, "import progress: %d lines read, %d articles imported, %d lines failed", p.Lines, p.Imported, p.Failed)
+		for _, f := range failures {
+			if len(response.Errors) == maxImportErrors {
+				break
+			}
+			response.Errors = append(response.Errors, importError(f))
+		}
+	})
+	response.ImportProgress = progress
+
+	status := http.StatusOK
+	if err != nil {
+		var appErr error
+		status, appErr = handleError(err)
+		if err != articles.ErrImportRead {
+			response.Errors = append(response.Errors, *appErr.(*errors.AppError))
+		}
+	} else if progress.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	negotiate.Render(c, status, response)
+}
+
+// importError translates the reason a line was not imported and ties it to the line
+func importError(f articles.ImportFailure) errors.AppError {
+	appErr, ok := f.Err.(*errors.AppError)
+	if !ok {
+		_, e := handleError(f.Err)
+		appErr = e.(*errors.AppError)
+	}
+
+	field := fmt.Sprintf("line %d", f.Line)
+	if appErr.Field != "" && appErr.Field != "unknown" {
+		field += "." + appErr.Field
+	}
+	return errors.AppError{Code: appErr.Code, Description: appErr.Description, Field: field}
+}
+
+// validateImport checks an imported article against the same rules a created article has to pass
+func validateImport(ar *articles.ArticleCreateUpdate) error {
+	if err := binding.Validator.ValidateStruct(ar); err != nil {
+		return &errors.AppError{Code: errors.BadRequest, Description: errors.Descriptions[errors.BadRequest]}
+	}
+	return nil
+}
+
+// ndjsonReader reads an article from every non-blank line of an NDJSON body
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxImportLineSize)
+	return &ndjsonReader{scanner: scanner}
+}
+
+// Read implements articles.ImportReader
+func (r *ndjsonReader) Read() (articles.ImportRecord, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		rec := articles.ImportRecord{Line: r.line}
+		if err := json.Unmarshal(line, &rec.Article); err != nil {
+			rec.Err = &errors.AppError{Code: errors.BadRequest, Description: "line is not a JSON article"}
+		} else {
+			rec.Err = validateImport(&rec.Article)
+		}
+		return rec, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			err = &errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("line is longer than %d bytes", maxImportLineSize)}
+		} else {
+			err = &errors.AppError{Code: errors.BadRequest, Description: "upload could not be read to the end"}
+		}
+		return articles.ImportRecord{Line: r.line + 1}, err
+	}
+	return articles.ImportRecord{}, io.EOF
+}
+
+const (
+	csvTitle     = "title"
+	csvBody      = "body"
+	csvTags      = "tags"
+	csvAuthorID  = "authorId"
+	csvPublishAt = "publishAt"
+	csvExpireAt  = "expireAt"
+
+	csvID        = "id"
+	csvSlug      = "slug"
+	csvStatus    = "status"
+	csvCreatedAt = "createdAt"
+	csvUpdatedAt = "updatedAt"
+	csvVersion   = "version"
+)
+
+var csvColumns = map[string]bool{
+	csvTitle:     true,
+	csvBody:      true,
+	csvTags:      true,
+	csvAuthorID:  true,
+	csvPublishAt: true,
+	csvExpireAt:  true,
+}
+
+// csvIgnoredColumns are the columns of a CSV export which an import cannot set, so an export can be imported
+// as it is. Their values are skipped: imported articles get a new id, slug and version and start out as drafts.
+var csvIgnoredColumns = map[string]bool{
+	csvID:        true,
+	csvSlug:      true,
+	csvStatus:    true,
+	csvCreatedAt: true,
+	csvUpdatedAt: true,
+	csvVersion:   true,
+}
+
+// csvReader reads an article from every record of a CSV body. Records are numbered as lines, starting with
+// the header on line 1, so a record with a quoted line break in it still counts as a single line.
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	line    int
+}
+
+// newCSVReader reads the header of the CSV body, which has to name the title and body columns
+// and may name the tags, authorId, publishAt and expireAt columns, in any order, along with the
+// csvIgnoredColumns of an export
+func newCSVReader(r io.Reader) (*csvReader, *errors.AppError) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, &errors.AppError{Code: errors.BadRequest, Description: "csv header could not be read", Field: "line 1"}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if csvIgnoredColumns[name] {
+			continue
+		}
+		if !csvColumns[name] {
+			return nil, &errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("unknown csv column %q", name), Field: "line 1"}
+		}
+		if _, ok := columns[name]; ok {
+			return nil, &errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("csv column %q appears more than once", name), Field: "line 1"}
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{csvTitle, csvBody} {
+		if _, ok := columns[name]; !ok {
+			return nil, &errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("csv column %q is required", name), Field: "line 1"}
+		}
+	}
+
+	return &csvReader{reader: reader, columns: columns, line: 1}, nil
+}
+
+// Read implements articles.ImportReader
+func (r *csvReader) Read() (articles.ImportRecord, error) {
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return articles.ImportRecord{}, io.EOF
+	}
+	r.line++
+
+	rec := articles.ImportRecord{Line: r.line}
+	if perr, ok := err.(*csv.ParseError); ok {
+		rec.Err = &errors.AppError{Code: errors.BadRequest, Description: perr.Err.Error()}
+		return rec, nil
+	}
+	if err != nil {
+		return rec, &errors.AppError{Code: errors.BadRequest, Description: "upload could not be read to the end"}
+	}
+
+	value := func(name string) string {
+		if i, ok := r.columns[name]; ok {
+			return record[i]
+		}
+		return ""
+	}
+
+	rec.Article = articles.ArticleCreateUpdate{
+		Title: value(csvTitle),
+		Body:  value(csvBody),
+	}
+	for _, tag := range strings.Split(value(csvTags), csvTagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			rec.Article.Tags = append(rec.Article.Tags, tag)
+		}
+	}
+	if authorID := strings.TrimSpace(value(csvAuthorID)); authorID != "" {
+		rec.Article.AuthorID = &authorID
+	}
+
+	if rec.Article.PublishAt, rec.Err = parseCSVTime(value(csvPublishAt), csvPublishAt); rec.Err != nil {
+		return rec, nil
+	}
+	if rec.Article.ExpireAt, rec.Err = parseCSVTime(value(csvExpireAt), csvExpireAt); rec.Err != nil {
+		return rec, nil
+	}
+
+	rec.Err = validateImport(&rec.Article)
+	return rec, nil
+}
+
+// parseCSVTime reads an optional RFC3339 timestamp from a CSV column
+func parseCSVTime(value, column string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, &errors.AppError{Code: errors.BadRequest, Description: column + " must be an RFC3339 timestamp", Field: column}
+	}
+	return &t, nil
+}
@@ -0,0 +1,239 @@
+package transport
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+// readAll drains the reader, the way the service does
+func readAll(r articles.ImportReader) ([]articles.ImportRecord, error) {
+	var records []articles.ImportRecord
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	body := `{"title": "first", "body": "body", "tags": ["go"]}
+
+{"title": "no body"}
+not json
+{"title": "last", "body": "body"}`
+
+	records, err := readAll(newNDJSONReader(strings.NewReader(body)))
+	require.NoError(t, err)
+
+	assert.Equal(t, []articles.ImportRecord{
+		{Line: 1, Article: articles.ArticleCreateUpdate{Title: "first", Body: "body", Tags: []string{"go"}}},
+		{Line: 3, Article: articles.ArticleCreateUpdate{Title: "no body"}, Err: &errors.AppError{Code: errors.BadRequest, Description: errors.Descriptions[errors.BadRequest]}},
+		{Line: 4, Err: &errors.AppError{Code: errors.BadRequest, Description: "line is not a JSON article"}},
+		{Line: 5, Article: articles.ArticleCreateUpdate{Title: "last", Body: "body"}},
+	}, records)
+}
+
+func TestNDJSONReaderLineTooLong(t *testing.T) {
+	body := `{"title": "first", "body": "body"}` + "\n" + strings.Repeat("x", maxImportLineSize+1)
+
+	records, err := readAll(newNDJSONReader(strings.NewReader(body)))
+
+	assert.Len(t, records, 1)
+	assert.Equal(t, &errors.AppError{Code: errors.BadRequest, Description: "line is longer than 8388608 bytes"}, err)
+}
+
+func TestCSVReader(t *testing.T) {
+	authorID := uuid.New().String()
+	publishAt := time.Date(2021, 4, 2, 12, 0, 0, 0, time.UTC)
+	body := "body,title,tags,authorId,publishAt\n" +
+		"\"multi\nline\",first,go; sql ;," + authorID + ",2021-04-02T12:00:00Z\n" +
+		"body,second,,,tomorrow\n" +
+		"body,too few\n" +
+		",no body,,,\n"
+
+	r, appErr := newCSVReader(strings.NewReader(body))
+	require.Nil(t, appErr)
+	records, err := readAll(r)
+	require.NoError(t, err)
+
+	require.Len(t, records, 4)
+	assert.Equal(t, articles.ImportRecord{Line: 2, Article: articles.ArticleCreateUpdate{
+		Title: "first", Body: "multi\nline", Tags: []string{"go", "sql"}, AuthorID: &authorID, PublishAt: &publishAt,
+	}}, records[0])
+	assert.Equal(t, &errors.AppError{Code: errors.BadRequest, Description: "publishAt must be an RFC3339 timestamp", Field: "publishAt"}, records[1].Err)
+	assert.Equal(t, 4, records[2].Line)
+	assert.Error(t, records[2].Err)
+	assert.Equal(t, &errors.AppError{Code: errors.BadRequest, Description: errors.Descriptions[errors.BadRequest]}, records[3].Err)
+}
+
+func TestCSVReaderHeader(t *testing.T) {
+	tests := map[string]struct {
+		header string
+		err    string
+	}{
+		"Unknown column":   {header: "title,body,summary", err: `unknown csv column "summary"`},
+		"Duplicate column": {header: "title,body,title", err: `csv column "title" appears more than once`},
+		"Missing column":   {header: "title,tags", err: `csv column "body" is required`},
+		"Empty":            {header: "", err: "csv header could not be read"},
+		"Export columns":   {header: "id,slug,title,status,version", err: `csv column "body" is required`},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, appErr := newCSVReader(strings.NewReader(test.header))
+
+			require.NotNil(t, appErr)
+			assert.Equal(t, test.err, appErr.Description)
+			assert.Equal(t, "line 1", appErr.Field)
+		})
+	}
+}
+
+func TestHandlerImport(t *testing.T) {
+	tests := map[string]struct {
+		mockService *mockService
+		contentType string
+		body        string
+		records     int
+		response    importResponse
+		status      int
+	}{
+		"NDJSON": {
+			mockService: &mockService{ImportResult: articles.ImportProgress{Lines: 2, Imported: 2}},
+			contentType: "application/x-ndjson",
+			body:        "{\"title\": \"a\", \"body\": \"b\"}\n{\"title\": \"c\", \"body\": \"d\"}\n",
+			records:     2,
+			response:    importResponse{ImportProgress: articles.ImportProgress{Lines: 2, Imported: 2}, AppErrors: errors.AppErrors{Errors: []errors.AppError{}}},
+			status:      http.StatusOK,
+		},
+		"CSV with failures": {
+			mockService: &mockService{
+				ImportResult: articles.ImportProgress{Lines: 2, Imported: 1, Failed: 2},
+				ImportFailures: []articles.ImportFailure{
+					{Line: 2, Err: articles.ErrArticleAuthorNotFound},
+					{Line: 3, Err: &errors.AppError{Code: errors.BadRequest, Description: "publishAt must be an RFC3339 timestamp", Field: "publishAt"}},
+				},
+			},
+			contentType: "text/csv; charset=utf-8",
+			body:        "title,body\na,b\nc,d\n",
+			records:     2,
+			response: importResponse{ImportProgress: articles.ImportProgress{Lines: 2, Imported: 1, Failed: 2}, AppErrors: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.NotFound, Description: articles.ErrArticleAuthorNotFound.Error(), Field: "line 2.authorId"},
+				{Code: errors.BadRequest, Description: "publishAt must be an RFC3339 timestamp", Field: "line 3.publishAt"},
+			}}},
+			status: http.StatusMultiStatus,
+		},
+		"Unreadable upload": {
+			mockService: &mockService{
+				ImportResult:   articles.ImportProgress{Lines: 1, Failed: 1},
+				ImportFailures: []articles.ImportFailure{{Line: 1, Err: &errors.AppError{Code: errors.BadRequest, Description: "line is longer than 8388608 bytes"}}},
+				ImportErr:      articles.ErrImportRead,
+			},
+			contentType: "application/x-ndjson",
+			body:        "{}",
+			records:     1,
+			response: importResponse{ImportProgress: articles.ImportProgress{Lines: 1, Failed: 1}, AppErrors: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "line is longer than 8388608 bytes", Field: "line 1"},
+			}}},
+			status: http.StatusBadRequest,
+		},
+		"Batch failure": {
+			mockService: &mockService{
+				ImportResult: articles.ImportProgress{Lines: 1},
+				ImportErr:    articles.ErrArticleBatch,
+			},
+			contentType: "application/x-ndjson",
+			body:        "{}",
+			records:     1,
+			response: importResponse{ImportProgress: articles.ImportProgress{Lines: 1}, AppErrors: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.InternalServerError, Description: articles.ErrArticleBatch.Error(), Field: "unknown"},
+			}}},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodPost, "/articles/import", strings.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Add("Content-Type", test.contentType)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+			assert.Len(t, test.mockService.ImportRecords, test.records)
+
+			var ir importResponse
+			if err := json.Unmarshal(response.Body.Bytes(), &ir); err != nil {
+				assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+			}
+			assert.Equal(t, test.response, ir)
+		})
+	}
+}
+
+func TestHandlerImportRejectsRequest(t *testing.T) {
+	tests := map[string]struct {
+		contentType string
+		body        string
+		response    errors.AppError
+		status      int
+	}{
+		"Unsupported content type": {
+			contentType: "application/xml",
+			body:        "<article/>",
+			response:    errors.AppError{Code: errors.UnsupportedMediaType, Description: "articles can be imported from application/x-ndjson or text/csv", Field: "Content-Type"},
+			status:      http.StatusUnsupportedMediaType,
+		},
+		"Bad CSV header": {
+			contentType: "text/csv",
+			body:        "title\n",
+			response:    errors.AppError{Code: errors.BadRequest, Description: `csv column "body" is required`, Field: "line 1"},
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, &mockService{}, &mockAuthorService{}, Config{})
+
+			req, err := http.NewRequest(http.MethodPost, "/articles/import", strings.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Add("Content-Type", test.contentType)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			var appErr errors.AppError
+			if err := json.Unmarshal(response.Body.Bytes(), &appErr); err != nil {
+				assert.Fail(t, "failed to unmarshal", response.Body.String(), err)
+			}
+			assert.Equal(t, test.response, appErr)
+		})
+	}
+}
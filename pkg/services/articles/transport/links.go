@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+const linkHeader = "Link"
+
+// pageLinks renders the Link header (RFC 8288) pointing at the first, previous and next pages of a listing,
+// keeping every other parameter of the request. A page picked by offset links to its neighbours by offset; a
+// page picked by cursor links to them by the cursors of the page.
+func pageLinks(u *url.URL, page articles.Articles) string {
+	link := func(rel string, set func(q url.Values)) string {
+		q := u.Query()
+		q.Del("cursor")
+		q.Del("offset")
+		set(q)
+		target := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+	}
+
+	links := []string{link("first", func(q url.Values) {})}
+	if page.Offset != nil && *page.Offset > 0 {
+		prev := *page.Offset - page.Limit
+		links = append(links, link("prev", func(q url.Values) {
+			if prev > 0 {
+				q.Set("offset", strconv.Itoa(prev))
+			}
+		}))
+	}
+	if page.PrevCursor != "" {
+		links = append(links, link("prev", func(q url.Values) {
+			q.Set("cursor", page.PrevCursor)
+		}))
+	}
+	if page.NextCursor != "" {
+		links = append(links, link("next", func(q url.Values) {
+			if page.Offset != nil {
+				q.Set("offset", strconv.Itoa(*page.Offset+page.Limit))
+				return
+			}
+			q.Set("cursor", page.NextCursor)
+		}))
+	}
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+func TestPageLinks(t *testing.T) {
+	zero, ten, fifty := 0, 10, 50
+
+	tests := map[string]struct {
+		uri   string
+		page  articles.PageInfo
+		links string
+	}{
+		"First page": {
+			uri:   "/articles/?limit=25&tag=go",
+			page:  articles.PageInfo{Limit: 25, Offset: &zero, NextCursor: "abc"},
+			links: `</articles/?limit=25&tag=go>; rel="first", </articles/?limit=25&offset=25&tag=go>; rel="next"`,
+		},
+		"Middle page": {
+			uri:   "/articles/?limit=25&offset=50",
+			page:  articles.PageInfo{Limit: 25, Offset: &fifty, NextCursor: "abc"},
+			links: `</articles/?limit=25>; rel="first", </articles/?limit=25&offset=25>; rel="prev", </articles/?limit=25&offset=75>; rel="next"`,
+		},
+		"Last page close to the start": {
+			uri:   "/articles/?limit=25&offset=10",
+			page:  articles.PageInfo{Limit: 25, Offset: &ten},
+			links: `</articles/?limit=25>; rel="first", </articles/?limit=25>; rel="prev"`,
+		},
+		"Cursor page": {
+			uri:   "/articles/?cursor=abc&sort=title",
+			page:  articles.PageInfo{Limit: 25, NextCursor: "def", PrevCursor: "ghi"},
+			links: `</articles/?sort=title>; rel="first", </articles/?cursor=ghi&sort=title>; rel="prev", </articles/?cursor=def&sort=title>; rel="next"`,
+		},
+		"First cursor page": {
+			uri:   "/articles/?cursor=abc",
+			page:  articles.PageInfo{Limit: 25, NextCursor: "def"},
+			links: `</articles/>; rel="first", </articles/?cursor=def>; rel="next"`,
+		},
+		"Empty cursor page": {
+			uri:   "/articles/?cursor=abc",
+			page:  articles.PageInfo{Limit: 25},
+			links: `</articles/>; rel="first"`,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			u, err := url.Parse(test.uri)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.links, pageLinks(u, articles.Articles{PageInfo: test.page}))
+		})
+	}
+}
+
+func TestHandlerGetAllPageInfo(t *testing.T) {
+	zero := 0
+	total := int64(120)
+	svc := &mockService{GetAllResult: articles.Articles{
+		Articles: []articles.Article{},
+		PageInfo: articles.PageInfo{Total: &total, TotalEstimated: true, Limit: 25, Offset: &zero, NextCursor: "abc"},
+	}}
+
+	response := httptest.NewRecorder()
+	router := gin.New()
+	newHandler(router, svc, &mockAuthorService{}, Config{})
+
+	req, err := http.NewRequest(http.MethodGet, "/articles/?total=estimate", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(response, req)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, articles.TotalEstimate, svc.GetAllParams.Total)
+	assert.Equal(t, `</articles/?total=estimate>; rel="first", </articles/?offset=25&total=estimate>; rel="next"`, response.Header().Get("Link"))
+	assert.JSONEq(t, `{"articles": [], "total": 120, "totalEstimated": true, "limit": 25, "offset": 0, "nextCursor": "abc"}`, response.Body.String())
+}
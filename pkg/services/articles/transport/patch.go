@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// parseMergePatch decodes a JSON Merge Patch (RFC 7386) document into an ArticlePatch. Members which
+// are absent are left untouched; since title and body are required, removing them (null) is rejected,
+// as are members that are not part of an article's editable fields. Tags are replaced as a whole
+// and removed by null, just like the publishAt and expireAt schedule.
+func parseMergePatch(data []byte) (articles.ArticlePatch, error) {
+	var p articles.ArticlePatch
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil || doc == nil {
+		return p, errors.NewAppError(errors.BadRequest, "merge patch must be a JSON object", "")
+	}
+
+	if raw, ok := doc["tags"]; ok {
+		tags, err := parseTagsPatch(raw)
+		if err != nil {
+			return p, err
+		}
+		p.Tags = &tags
+		delete(doc, "tags")
+	}
+
+	var err error
+	if p.PublishAt, err = takeTimePatch(doc, "publishAt"); err != nil {
+		return p, err
+	}
+	if p.ExpireAt, err = takeTimePatch(doc, "expireAt"); err != nil {
+		return p, err
+	}
+
+	fields := map[string]**string{
+		"title": &p.Title,
+		"body":  &p.Body,
+	}
+
+	keys := make([]string, 0, len(doc))
+	for k := range doc {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		dst, ok := fields[k]
+		if !ok {
+			return p, errors.NewAppError(errors.BadRequest, "field cannot be patched", k)
+		}
+
+		raw := doc[k]
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			return p, errors.NewAppError(errors.BadRequest, "required field cannot be removed", k)
+		}
+
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil || v == "" {
+			return p, errors.NewAppError(errors.BadRequest, "field must be a non-empty string", k)
+		}
+		*dst = &v
+	}
+
+	return p, nil
+}
+
+// takeTimePatch removes the named member from the document and decodes it as an RFC3339 timestamp,
+// where null clears the time. Nothing is returned when the member is absent.
+func takeTimePatch(doc map[string]json.RawMessage, field string) (**time.Time, error) {
+	raw, ok := doc[field]
+	if !ok {
+		return nil, nil
+	}
+	delete(doc, field)
+
+	var t *time.Time
+	if !bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		t = new(time.Time)
+		if err := json.Unmarshal(raw, t); err != nil {
+			return nil, errors.NewAppError(errors.BadRequest, "field must be an RFC3339 timestamp or null", field)
+		}
+	}
+	return &t, nil
+}
+
+// parseTagsPatch decodes the new tags of an article, where null removes all tags
+func parseTagsPatch(raw json.RawMessage) ([]string, error) {
+	tags := make([]string, 0)
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return tags, nil
+	}
+
+	if err := json.Unmarshal(raw, &tags); err != nil {
+		return nil, errors.NewAppError(errors.BadRequest, "tags must be an array of strings", "tags")
+	}
+	for _, t := range tags {
+		if t == "" || utf8.RuneCountInString(t) > articles.MaxTagLength {
+			return nil, errors.NewAppError(errors.BadRequest, fmt.Sprintf("tags must be non-empty and at most %d characters long", articles.MaxTagLength), "tags")
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,24 @@
+package transport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMergePatchSchedule(t *testing.T) {
+	p, err := parseMergePatch([]byte(`{"publishAt": "2021-04-02T12:00:00Z", "expireAt": null}`))
+	require.NoError(t, err)
+
+	require.NotNil(t, p.PublishAt)
+	require.NotNil(t, *p.PublishAt)
+	assert.True(t, (*p.PublishAt).Equal(time.Date(2021, 4, 2, 12, 0, 0, 0, time.UTC)))
+
+	require.NotNil(t, p.ExpireAt, "a null expireAt clears the expiry")
+	assert.Nil(t, *p.ExpireAt)
+
+	assert.Nil(t, p.Title)
+	assert.False(t, p.Empty())
+}
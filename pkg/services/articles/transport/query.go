@@ -0,0 +1,316 @@
+package transport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/paging"
+)
+
+// parseRevision reads a revision number, which counts up from 1, from the named parameter
+func parseRevision(value, field string) (int, *errors.AppError) {
+	rev, err := strconv.Atoi(value)
+	if err != nil || rev < 1 {
+		return 0, &errors.AppError{Code: errors.BadRequest, Description: "revision must be a positive number", Field: field}
+	}
+	return rev, nil
+}
+
+// listQuery holds the query parameters accepted when listing articles.
+// Unless statuses are asked for, only published articles are listed.
+type listQuery struct {
+	paging.Query
+	Cursor          string   `form:"cursor"`
+	Sort            string   `form:"sort"`
+	IncludeDisabled bool     `form:"includeDisabled,default=false"`
+	Q               string   `form:"q"`
+	AsOf            string   `form:"asOf"`
+	Tags            []string `form:"tag"`
+	TagMatch        string   `form:"tagMatch,default=any"`
+	Expand          string   `form:"expand"`
+	Status          []string `form:"status"`
+	Fields          string   `form:"fields"`
+	Total           string   `form:"total"`
+}
+
+// searchQuery turns a listing with a q parameter into a full-text search. Search results are
+// ordered by rank and never include disabled articles, so those parameters are rejected.
+func (q *listQuery) searchQuery() (searchQuery, []errors.AppError) {
+	var errs []errors.AppError
+	if q.Sort != "" {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "search results cannot be sorted", Field: "sort"})
+	}
+	if q.IncludeDisabled {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "search results cannot include disabled articles", Field: "includeDisabled"})
+	}
+	if q.AsOf != "" {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "search results are only available for the current articles", Field: "asOf"})
+	}
+	if len(q.Tags) > 0 {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "search results cannot be filtered by tag", Field: "tag"})
+	}
+	if q.Expand != "" {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "search results cannot be expanded", Field: "expand"})
+	}
+	if len(q.Status) > 0 {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "search results only include published articles", Field: "status"})
+	}
+	if q.Fields != "" {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "search results cannot be narrowed down to fields", Field: "fields"})
+	}
+
+	return searchQuery{
+		Query:  q.Query,
+		Cursor: q.Cursor,
+		Q:      q.Q,
+		Mode:   searchModeFullText,
+		Total:  q.Total,
+	}, errs
+}
+
+// params validates the query and translates it into ListParams for the service
+func (q *listQuery) params() (articles.ListParams, []errors.AppError) {
+	errs := q.Validate()
+	p := articles.ListParams{
+		Limit:           q.Limit,
+		Offset:          q.Offset,
+		IncludeDisabled: q.IncludeDisabled,
+	}
+
+	sort, sortErr := parseSort(q.Sort)
+	if sortErr != nil {
+		errs = append(errs, *sortErr)
+	}
+	p.Sort = sort
+
+	if q.Cursor != "" && sortErr == nil {
+		c, err := articles.DecodeCursor(q.Cursor, sort)
+		if err != nil {
+			errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: err.Error(), Field: "cursor"})
+		}
+		if q.Offset != 0 {
+			errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "offset cannot be combined with cursor", Field: "offset"})
+		}
+		p.Cursor = &c
+	}
+
+	asOf, err := parseAsOf(q.AsOf)
+	if err != nil {
+		errs = append(errs, *err)
+	}
+	p.AsOf = asOf
+
+	p.Tags = articles.NormalizeTags(q.Tags)
+	for _, t := range p.Tags {
+		if utf8.RuneCountInString(t) > articles.MaxTagLength {
+			errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("tag %q is too long", t), Field: "tag"})
+		}
+	}
+
+	match, ok := tagMatches[q.TagMatch]
+	if !ok {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("unknown tag match %q", q.TagMatch), Field: "tagMatch"})
+	}
+	p.TagMatch = match
+
+	p.Statuses = []articles.Status{articles.StatusPublished}
+	if len(q.Status) > 0 {
+		p.Statuses = nil
+	}
+	for _, name := range q.Status {
+		status := articles.Status(name)
+		if !articles.Statuses[status] {
+			errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("unknown status %q", name), Field: "status"})
+			continue
+		}
+		p.Statuses = append(p.Statuses, status)
+	}
+
+	fields, fieldsErr := parseFields(q.Fields)
+	if fieldsErr != nil {
+		errs = append(errs, *fieldsErr)
+	}
+	p.Fields = fields
+
+	total, ok := totalCounts[q.Total]
+	if !ok {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("unknown total %q", q.Total), Field: "total"})
+	}
+	p.Total = total
+
+	return p, errs
+}
+
+var tagMatches = map[string]articles.TagMatch{
+	"any": articles.TagMatchAny,
+	"all": articles.TagMatchAll,
+}
+
+// totalCounts are the ways the total of a listing can be asked for, counting exactly
+// unless there are too many articles to do so quickly when nothing is asked for
+var totalCounts = map[string]articles.TotalCount{
+	"":         articles.TotalAuto,
+	"auto":     articles.TotalAuto,
+	"exact":    articles.TotalExact,
+	"estimate": articles.TotalEstimate,
+	"none":     articles.TotalNone,
+}
+
+// parseAsOf reads the optional RFC3339 timestamp of a point-in-time read
+func parseAsOf(value string) (*time.Time, *errors.AppError) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, &errors.AppError{Code: errors.BadRequest, Description: "asOf must be an RFC3339 timestamp", Field: "asOf"}
+	}
+	return &t, nil
+}
+
+// formatHTML asks for the body of an article to be rendered to HTML as well
+const formatHTML = "html"
+
+// parseFormat reads the optional format of the body of an article, which is Markdown unless HTML is asked for
+func parseFormat(value string) (bool, *errors.AppError) {
+	switch value {
+	case "":
+		return false, nil
+	case formatHTML:
+		return true, nil
+	default:
+		return false, &errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("unknown format %q", value), Field: "format"}
+	}
+}
+
+// parseSort turns a sort parameter like "-updatedAt,title" into sort fields, where a leading "-"
+// sorts in descending order. Only the whitelisted articles.SortableFields are accepted.
+func parseSort(param string) ([]articles.SortField, *errors.AppError) {
+	if strings.TrimSpace(param) == "" {
+		return articles.DefaultSort, nil
+	}
+
+	var sort []articles.SortField
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(param, ",") {
+		key = strings.TrimSpace(key)
+
+		var s articles.SortField
+		switch {
+		case strings.HasPrefix(key, "-"):
+			s = articles.SortField{Field: key[1:], Desc: true}
+		case strings.HasPrefix(key, "+"):
+			s = articles.SortField{Field: key[1:]}
+		default:
+			s = articles.SortField{Field: key}
+		}
+
+		if !articles.SortableFields[s.Field] {
+			return nil, &errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("cannot sort by %q", s.Field), Field: "sort"}
+		}
+		if seen[s.Field] {
+			return nil, &errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("cannot sort by %q more than once", s.Field), Field: "sort"}
+		}
+		seen[s.Field] = true
+		sort = append(sort, s)
+	}
+	return sort, nil
+}
+
+const (
+	searchModeFuzzy    = "fuzzy"
+	searchModeFullText = "fulltext"
+)
+
+var searchModes = map[string]articles.SearchMode{
+	searchModeFuzzy:    articles.SearchFuzzy,
+	searchModeFullText: articles.SearchFullText,
+}
+
+// searchQuery holds the query parameters accepted when searching articles, which are paged and counted
+// the way listings are
+type searchQuery struct {
+	paging.Query
+	Cursor      string `form:"cursor"`
+	Q           string `form:"q"`
+	Mode        string `form:"mode,default=fuzzy"`
+	IncludeBody bool   `form:"body,default=false"`
+	Total       string `form:"total"`
+}
+
+// params validates the query and translates it into SearchParams for the service
+func (q *searchQuery) params(threshold float64) (articles.SearchParams, []errors.AppError) {
+	errs := q.Validate()
+	p := articles.SearchParams{
+		Query:       strings.TrimSpace(q.Q),
+		IncludeBody: q.IncludeBody,
+		Threshold:   threshold,
+		Limit:       q.Limit,
+		Offset:      q.Offset,
+	}
+
+	if p.Query == "" {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "search query is required", Field: "q"})
+	}
+
+	mode, ok := searchModes[q.Mode]
+	if !ok {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("unknown search mode %q", q.Mode), Field: "mode"})
+	}
+	p.Mode = mode
+
+	if q.Cursor != "" {
+		c, err := articles.DecodeSearchCursor(q.Cursor)
+		if err != nil {
+			errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: err.Error(), Field: "cursor"})
+		}
+		if q.Offset != 0 {
+			errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "offset cannot be combined with cursor", Field: "offset"})
+		}
+		p.Cursor = &c
+	}
+
+	total, ok := totalCounts[q.Total]
+	if !ok {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("unknown total %q", q.Total), Field: "total"})
+	}
+	p.Total = total
+
+	return p, errs
+}
+
+var diffUnits = map[string]articles.DiffUnit{
+	"line": articles.DiffLines,
+	"word": articles.DiffWords,
+}
+
+// diffQuery holds the query parameters accepted when comparing revisions
+type diffQuery struct {
+	From               string `form:"from"`
+	To                 string `form:"to"`
+	Unit               string `form:"unit,default=line"`
+	IncludeUnpublished bool   `form:"includeUnpublished,default=false"`
+}
+
+// params validates the revisions to compare and the granularity of the comparison
+func (q *diffQuery) params() (from, to int, unit articles.DiffUnit, errs []errors.AppError) {
+	from, err := parseRevision(q.From, "from")
+	if err != nil {
+		errs = append(errs, *err)
+	}
+	to, err = parseRevision(q.To, "to")
+	if err != nil {
+		errs = append(errs, *err)
+	}
+
+	unit, ok := diffUnits[q.Unit]
+	if !ok {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: fmt.Sprintf("unknown diff unit %q", q.Unit), Field: "unit"})
+	}
+	return from, to, unit, errs
+}
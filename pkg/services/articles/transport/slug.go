@@ -0,0 +1,42 @@
+package transport
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kott/go-service-example/pkg/utils/context"
+	"github.com/kott/go-service-example/pkg/utils/log"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
+)
+
+// articleID returns the id of the article the :id parameter refers to, which is either its id or
+// one of its slugs. Requests for an earlier slug are redirected to the current one, in which case
+// ok is false and the response has been written.
+func (h *handler) articleID(c *gin.Context) (id string, ok bool) {
+	param := c.Param("id")
+	if _, err := uuid.Parse(param); err == nil {
+		return param, true
+	}
+
+	ctx := context.GetReqCtx(c)
+	id, canonical, err := h.ArticleService.ResolveSlug(ctx, param)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return "", false
+	}
+
+	if canonical != param {
+		log.Info(ctx, "redirecting slug %s to %s", param, canonical)
+		location := "/articles/" + url.PathEscape(canonical)
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return "", false
+	}
+	return id, true
+}
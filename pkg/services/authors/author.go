@@ -0,0 +1,85 @@
+package authors
+
+import (
+	"context"
+)
+
+// Repo defines the DB level interaction of authors
+type Repo interface {
+	Get(ctx context.Context, id string) (Author, error)
+	GetAll(ctx context.Context, limit, offset int) ([]Author, error)
+	GetMany(ctx context.Context, ids []string) ([]Author, error)
+	Create(ctx context.Context, au AuthorCreateUpdate) (string, error)
+	Update(ctx context.Context, au AuthorCreateUpdate, id string) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Service defines the service level contract that other services
+// outside this package can use to interact with Author resources
+type Service interface {
+	Get(ctx context.Context, id string) (Author, error)
+	GetAll(ctx context.Context, limit, offset int) ([]Author, error)
+	Summaries(ctx context.Context, ids []string) (map[string]Summary, error)
+	Create(ctx context.Context, au AuthorCreateUpdate) (Author, error)
+	Update(ctx context.Context, au AuthorCreateUpdate, id string) (Author, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type author struct {
+	repo Repo
+}
+
+// New Service instance
+func New(repo Repo) Service {
+	return &author{repo}
+}
+
+// Get sends the request straight to the repo
+func (s *author) Get(ctx context.Context, id string) (Author, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// GetAll sends the request straight to the repo
+func (s *author) GetAll(ctx context.Context, limit, offset int) ([]Author, error) {
+	return s.repo.GetAll(ctx, limit, offset)
+}
+
+// Summaries looks up the summaries of the authors with the given ids, keyed by id.
+// Ids of authors which do not exist are left out.
+func (s *author) Summaries(ctx context.Context, ids []string) (map[string]Summary, error) {
+	summaries := make(map[string]Summary, len(ids))
+	if len(ids) == 0 {
+		return summaries, nil
+	}
+
+	al, err := s.repo.GetMany(ctx, ids)
+	if err != nil {
+		return summaries, err
+	}
+	for _, a := range al {
+		summaries[a.ID] = a.Summary()
+	}
+	return summaries, nil
+}
+
+// Create passes of the created to the repo and retrieves the newly created record
+func (s *author) Create(ctx context.Context, au AuthorCreateUpdate) (Author, error) {
+	id, err := s.repo.Create(ctx, au)
+	if err != nil {
+		return Author{}, err
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// Update the requested resource and retrieve it
+func (s *author) Update(ctx context.Context, au AuthorCreateUpdate, id string) (Author, error) {
+	if err := s.repo.Update(ctx, au, id); err != nil {
+		return Author{}, err
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// Delete removes the author. Their articles are kept but no longer linked to an author.
+func (s *author) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
@@ -0,0 +1,151 @@
+package authors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type repoMock struct {
+	GetResult Author
+	GetError  error
+
+	GetAllResult []Author
+	GetAllError  error
+
+	GetManyResult []Author
+	GetManyError  error
+
+	CreateResult string
+	CreateError  error
+
+	UpdateError error
+
+	DeleteError error
+}
+
+func (r *repoMock) Get(ctx context.Context, id string) (Author, error) {
+	return r.GetResult, r.GetError
+}
+
+func (r *repoMock) GetAll(ctx context.Context, limit, offset int) ([]Author, error) {
+	return r.GetAllResult, r.GetAllError
+}
+
+func (r *repoMock) GetMany(ctx context.Context, ids []string) ([]Author, error) {
+	return r.GetManyResult, r.GetManyError
+}
+
+func (r *repoMock) Create(ctx context.Context, au AuthorCreateUpdate) (string, error) {
+	return r.CreateResult, r.CreateError
+}
+
+func (r *repoMock) Update(ctx context.Context, au AuthorCreateUpdate, id string) error {
+	return r.UpdateError
+}
+
+func (r *repoMock) Delete(ctx context.Context, id string) error {
+	return r.DeleteError
+}
+
+func TestServiceSummaries(t *testing.T) {
+	id := uuid.New().String()
+	other := uuid.New().String()
+	tests := map[string]struct {
+		repo   Repo
+		ids    []string
+		result map[string]Summary
+		err    error
+	}{
+		"Happy path": {
+			repo:   &repoMock{GetManyResult: []Author{{ID: id, Name: "name", Bio: "bio"}}},
+			ids:    []string{id, other},
+			result: map[string]Summary{id: {ID: id, Name: "name"}},
+			err:    nil,
+		},
+		"No ids": {
+			repo:   &repoMock{GetManyError: ErrAuthorQuery},
+			ids:    nil,
+			result: map[string]Summary{},
+			err:    nil,
+		},
+		"Query failure": {
+			repo:   &repoMock{GetManyError: ErrAuthorQuery},
+			ids:    []string{id},
+			result: map[string]Summary{},
+			err:    ErrAuthorQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.Summaries(context.Background(), test.ids)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
+
+func TestServiceCreate(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		repo   Repo
+		result Author
+		err    error
+	}{
+		"Happy path": {
+			repo:   &repoMock{CreateResult: id, GetResult: Author{ID: id, Name: "name"}},
+			result: Author{ID: id, Name: "name"},
+			err:    nil,
+		},
+		"Create failure": {
+			repo:   &repoMock{CreateError: ErrAuthorCreate},
+			result: Author{},
+			err:    ErrAuthorCreate,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.Create(context.Background(), AuthorCreateUpdate{Name: "name"})
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
+
+func TestServiceUpdate(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		repo   Repo
+		result Author
+		err    error
+	}{
+		"Happy path": {
+			repo:   &repoMock{GetResult: Author{ID: id, Name: "new name"}},
+			result: Author{ID: id, Name: "new name"},
+			err:    nil,
+		},
+		"Not found": {
+			repo:   &repoMock{UpdateError: ErrAuthorNotFound},
+			result: Author{},
+			err:    ErrAuthorNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo)
+			response, err := service.Update(context.Background(), AuthorCreateUpdate{Name: "new name"}, id)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package authors
+
+import (
+	"errors"
+)
+
+var (
+	// ErrAuthorNotFound ...
+	ErrAuthorNotFound = errors.New("requested author could not be found")
+
+	// ErrAuthorQuery ...
+	ErrAuthorQuery = errors.New("requested authors could not be retrieved base on the given criteria")
+
+	// ErrAuthorCreate ...
+	ErrAuthorCreate = errors.New("author could not be created")
+
+	// ErrAuthorUpdate ...
+	ErrAuthorUpdate = errors.New("author could not be updated")
+
+	// ErrAuthorDelete ...
+	ErrAuthorDelete = errors.New("author could not be deleted")
+)
@@ -0,0 +1,38 @@
+package authors
+
+import "time"
+
+// Author is the nominal object used for interacting with authors.
+// This represents what is stored in the database.
+type Author struct {
+	ID string `json:"id"`
+
+	Name string `json:"name"`
+	Bio  string `json:"bio"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Authors is used to present a list of authors in a JSON response.
+type Authors struct {
+	Authors []Author `json:"authors"`
+}
+
+// AuthorCreateUpdate is the request body that is
+// accepted for create and updates to authors.
+type AuthorCreateUpdate struct {
+	Name string `json:"name" binding:"required"`
+	Bio  string `json:"bio"`
+}
+
+// Summary is the part of an author which is embedded in the resources they are linked to.
+type Summary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Summary reduces the author to its summary
+func (a Author) Summary() Summary {
+	return Summary{ID: a.ID, Name: a.Name}
+}
@@ -0,0 +1,152 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"github.com/kott/go-service-example/pkg/services/authors"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+// authorColumns are the columns scanned into an authors.Author, in scan order
+const authorColumns = `id, name, bio, created_at, updated_at`
+
+const (
+	selectAuthor       = `SELECT ` + authorColumns + ` FROM authors WHERE id = $1`
+	selectManyAuthors  = `SELECT ` + authorColumns + ` FROM authors ORDER BY name, id LIMIT $1 OFFSET $2`
+	selectAuthorsByIDs = `SELECT ` + authorColumns + ` FROM authors WHERE id = ANY($1::uuid[])`
+	insertAuthor       = `INSERT INTO authors (name, bio, created_at, updated_at) VALUES ($1, $2, now(), now()) RETURNING id`
+	updateAuthor       = `UPDATE authors SET name = $1, bio = $2, updated_at = now() WHERE id = $3`
+	deleteAuthor       = `DELETE FROM authors WHERE id = $1`
+
+	// unlinkAuthorArticles is a change to each article of the author, so it bumps their version like any other
+	unlinkAuthorArticles = `UPDATE articles SET author_id = NULL, version = version + 1, updated_at = now() WHERE author_id = $1`
+)
+
+type authorRepo struct {
+	DB *sql.DB
+}
+
+// New creates an instance of the authorRepo.
+func New(conn *sql.DB) authors.Repo {
+	return &authorRepo{conn}
+}
+
+// Get retrieves the author with the given id
+func (r *authorRepo) Get(ctx context.Context, id string) (authors.Author, error) {
+	var a authors.Author
+
+	err := r.DB.QueryRow(selectAuthor, id).
+		Scan(&a.ID, &a.Name, &a.Bio, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		log.Info(ctx, "select author error: %s", err.Error())
+		return a, authors.ErrAuthorNotFound
+	}
+
+	return a, nil
+}
+
+// GetAll retrieves the authors within the limit and offset, ordered by name
+func (r *authorRepo) GetAll(ctx context.Context, limit, offset int) ([]authors.Author, error) {
+	return r.query(ctx, selectManyAuthors, limit, offset)
+}
+
+// GetMany retrieves the authors with the given ids, in no particular order
+func (r *authorRepo) GetMany(ctx context.Context, ids []string) ([]authors.Author, error) {
+	return r.query(ctx, selectAuthorsByIDs, pq.Array(ids))
+}
+
+func (r *authorRepo) query(ctx context.Context, query string, args ...interface{}) ([]authors.Author, error) {
+	al := make([]authors.Author, 0)
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		log.Warn(ctx, "unable to query db: %s", err.Error())
+		return al, authors.ErrAuthorQuery
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a authors.Author
+		if err := rows.Scan(&a.ID, &a.Name, &a.Bio, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			log.Error(ctx, "unable to scan db rows: %s", err.Error())
+			return al, authors.ErrAuthorQuery
+		}
+
+		al = append(al, a)
+	}
+
+	return al, nil
+}
+
+// Create sets the name and bio in a new db record
+func (r *authorRepo) Create(ctx context.Context, au authors.AuthorCreateUpdate) (string, error) {
+	var id string
+	if err := r.DB.QueryRow(insertAuthor, au.Name, au.Bio).Scan(&id); err != nil {
+		log.Error(ctx, "unable to create author: %s", err.Error())
+		return "", authors.ErrAuthorCreate
+	}
+
+	log.Info(ctx, "created author with id=%s", id)
+	return id, nil
+}
+
+// Update sets the name and bio on an existing record
+func (r *authorRepo) Update(ctx context.Context, au authors.AuthorCreateUpdate, id string) error {
+	res, err := r.DB.Exec(updateAuthor, au.Name, au.Bio, id)
+	if err != nil {
+		log.Error(ctx, "unable to update author (%s): %s", id, err.Error())
+		return authors.ErrAuthorUpdate
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Error(ctx, "unable to update author (%s): %s", id, err.Error())
+		return authors.ErrAuthorUpdate
+	}
+	if n == 0 {
+		return authors.ErrAuthorNotFound
+	}
+	return nil
+}
+
+// Delete removes the author. Their articles are unlinked within the same transaction, before the author is
+// removed, so the entity tags and update times of those articles change along with their author.
+func (r *authorRepo) Delete(ctx context.Context, id string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error(ctx, "unable to begin transaction: %s", err.Error())
+		return authors.ErrAuthorDelete
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(unlinkAuthorArticles, id); err != nil {
+		log.Error(ctx, "unable to unlink the articles of author (%s): %s", id, err.Error())
+		return authors.ErrAuthorDelete
+	}
+
+	res, err := tx.Exec(deleteAuthor, id)
+	if err != nil {
+		log.Error(ctx, "unable to delete author (%s): %s", id, err.Error())
+		return authors.ErrAuthorDelete
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Error(ctx, "unable to delete author (%s): %s", id, err.Error())
+		return authors.ErrAuthorDelete
+	}
+	if n == 0 {
+		return authors.ErrAuthorNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(ctx, "unable to commit transaction: %s", err.Error())
+		return authors.ErrAuthorDelete
+	}
+
+	log.Info(ctx, "deleted author with id=%s", id)
+	return nil
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/authors"
+)
+
+func TestAuthorRepoGet(t *testing.T) {
+	columns := []string{"id", "name", "bio", "created_at", "updated_at"}
+	id := uuid.New().String()
+	now := time.Now()
+
+	tests := map[string]struct {
+		expectQueryRows  *sqlmock.Rows
+		expectQueryError error
+		expect           authors.Author
+		err              error
+	}{
+		"Happy path": {
+			expectQueryRows: sqlmock.NewRows(columns).AddRow(id, "name", "bio", now, now),
+			expect:          authors.Author{ID: id, Name: "name", Bio: "bio", CreatedAt: now, UpdatedAt: now},
+		},
+		"Not found": {
+			expectQueryRows: sqlmock.NewRows(columns),
+			err:             authors.ErrAuthorNotFound,
+		},
+		"Query error": {
+			expectQueryError: errors.New("some-db-error"),
+			err:              authors.ErrAuthorNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			q := mock.ExpectQuery(regexp.QuoteMeta(selectAuthor)).WithArgs(id).WillReturnError(test.expectQueryError)
+			if test.expectQueryRows != nil {
+				q.WillReturnRows(test.expectQueryRows)
+			}
+
+			repo := New(db)
+			response, err := repo.Get(context.Background(), id)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expect, response)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthorRepoGetMany(t *testing.T) {
+	columns := []string{"id", "name", "bio", "created_at", "updated_at"}
+	id := uuid.New().String()
+	now := time.Now()
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectAuthorsByIDs)).WithArgs(pq.Array([]string{id})).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(id, "name", "", now, now))
+
+	repo := New(db)
+	response, err := repo.GetMany(context.Background(), []string{id})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []authors.Author{{ID: id, Name: "name", CreatedAt: now, UpdatedAt: now}}, response)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthorRepoUpdate(t *testing.T) {
+	id := uuid.New().String()
+
+	tests := map[string]struct {
+		expectExecResult driver.Result
+		expectExecError  error
+		err              error
+	}{
+		"Happy path": {
+			expectExecResult: sqlmock.NewResult(0, 1),
+			err:              nil,
+		},
+		"Not found": {
+			expectExecResult: sqlmock.NewResult(0, 0),
+			err:              authors.ErrAuthorNotFound,
+		},
+		"Update error": {
+			expectExecError: errors.New("some-db-error"),
+			err:             authors.ErrAuthorUpdate,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(updateAuthor)).WithArgs("name", "bio", id).WillReturnError(test.expectExecError).WillReturnResult(test.expectExecResult)
+
+			repo := New(db)
+			err := repo.Update(context.Background(), authors.AuthorCreateUpdate{Name: "name", Bio: "bio"}, id)
+
+			assert.Equal(t, test.err, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthorRepoDelete(t *testing.T) {
+	id := uuid.New().String()
+
+	tests := map[string]struct {
+		expectUnlinkError error
+		expectExecResult  driver.Result
+		expectExecError   error
+		expectCommit      bool
+		err               error
+	}{
+		"Happy path": {
+			expectExecResult: sqlmock.NewResult(0, 1),
+			expectCommit:     true,
+			err:              nil,
+		},
+		"Not found": {
+			expectExecResult: sqlmock.NewResult(0, 0),
+			err:              authors.ErrAuthorNotFound,
+		},
+		"Unlink error": {
+			expectUnlinkError: errors.New("some-db-error"),
+			err:               authors.ErrAuthorDelete,
+		},
+		"Delete error": {
+			expectExecError: errors.New("some-db-error"),
+			err:             authors.ErrAuthorDelete,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(unlinkAuthorArticles)).WithArgs(id).WillReturnError(test.expectUnlinkError).WillReturnResult(sqlmock.NewResult(0, 2))
+			if test.expectUnlinkError == nil {
+				mock.ExpectExec(regexp.QuoteMeta(deleteAuthor)).WithArgs(id).WillReturnError(test.expectExecError).WillReturnResult(test.expectExecResult)
+			}
+			if test.expectCommit {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			repo := New(db)
+			err := repo.Delete(context.Background(), id)
+
+			assert.Equal(t, test.err, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package transport
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/authors"
+	"github.com/kott/go-service-example/pkg/services/authors/store"
+	"github.com/kott/go-service-example/pkg/utils/context"
+	"github.com/kott/go-service-example/pkg/utils/log"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
+	"github.com/kott/go-service-example/pkg/utils/paging"
+)
+
+type handler struct {
+	AuthorService authors.Service
+}
+
+// Activate sets all the services required for authors and registers all the endpoints with the engine.
+func Activate(router *gin.Engine, db *sql.DB) {
+	authorService := authors.New(store.New(db))
+	newHandler(router, authorService)
+}
+
+func newHandler(router *gin.Engine, as authors.Service) {
+	h := handler{
+		AuthorService: as,
+	}
+
+	router.GET("/authors/:id", h.Get)
+	router.GET("/authors/", h.GetAll)
+	router.POST("/authors/", h.Create)
+	router.PUT("/authors/:id", h.Update)
+	router.DELETE("/authors/:id", h.Delete)
+}
+
+func (h *handler) Get(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+
+	log.Info(ctx, "retrieving author id=%s", c.Param("id"))
+	author, err := h.AuthorService.Get(ctx, c.Param("id"))
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, author)
+}
+
+func (h *handler) GetAll(c *gin.Context) {
+	var q paging.Query
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	if errs := q.Validate(); len(errs) > 0 {
+		log.Info(ctx, "invalid list query: %v", errs)
+		negotiate.Render(c, http.StatusBadRequest, errors.AppErrors{Errors: errs})
+		return
+	}
+
+	log.Info(ctx, "retrieving all authors: offset=%d limit=%d", q.Offset, q.Limit)
+	al, err := h.AuthorService.GetAll(ctx, q.Limit, q.Offset)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, authors.Authors{Authors: al})
+}
+
+func (h *handler) Create(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+
+	var ac authors.AuthorCreateUpdate
+	if err := c.ShouldBindJSON(&ac); err != nil {
+		log.Info(ctx, "request parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	log.Info(ctx, "creating author %v", ac)
+	author, err := h.AuthorService.Create(ctx, ac)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusCreated, author)
+}
+
+func (h *handler) Update(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+
+	var ac authors.AuthorCreateUpdate
+	if err := c.ShouldBindJSON(&ac); err != nil {
+		log.Info(ctx, "request parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	id := c.Param("id")
+
+	log.Info(ctx, "updating author %s with %v", id, ac)
+	author, err := h.AuthorService.Update(ctx, ac, id)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, author)
+}
+
+func (h *handler) Delete(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+	id := c.Param("id")
+
+	log.Info(ctx, "deleting author %s", id)
+	if err := h.AuthorService.Delete(ctx, id); err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleError allows us to map errors defined internally to appropriate HTTP error codes and JSON responses
+func handleError(e error) (int, error) {
+	switch e {
+	case authors.ErrAuthorNotFound:
+		return http.StatusNotFound, errors.NewAppError(errors.NotFound, e.Error(), "id")
+	case authors.ErrAuthorUpdate:
+		fallthrough
+	case authors.ErrAuthorCreate:
+		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, "unable to create/update author", "")
+	case authors.ErrAuthorDelete:
+		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, "unable to delete author", "")
+	default:
+		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, e.Error(), "unknown")
+	}
+}
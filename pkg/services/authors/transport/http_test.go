@@ -0,0 +1,238 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/authors"
+)
+
+type mockService struct {
+	GetResult    authors.Author
+	GetErr       error
+	GetAllResult []authors.Author
+	GetAllErr    error
+	CreateResult authors.Author
+	CreateErr    error
+	UpdateResult authors.Author
+	UpdateErr    error
+	DeleteErr    error
+}
+
+func (s *mockService) Get(ctx context.Context, id string) (authors.Author, error) {
+	return s.GetResult, s.GetErr
+}
+
+func (s *mockService) GetAll(ctx context.Context, limit, offset int) ([]authors.Author, error) {
+	return s.GetAllResult, s.GetAllErr
+}
+
+func (s *mockService) Summaries(ctx context.Context, ids []string) (map[string]authors.Summary, error) {
+	return nil, nil
+}
+
+func (s *mockService) Create(ctx context.Context, au authors.AuthorCreateUpdate) (authors.Author, error) {
+	return s.CreateResult, s.CreateErr
+}
+
+func (s *mockService) Update(ctx context.Context, au authors.AuthorCreateUpdate, id string) (authors.Author, error) {
+	return s.UpdateResult, s.UpdateErr
+}
+
+func (s *mockService) Delete(ctx context.Context, id string) error {
+	return s.DeleteErr
+}
+
+func TestHandlerGet(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		mockService authors.Service
+		response    interface{}
+		status      int
+	}{
+		"Happy path": {
+			mockService: &mockService{GetResult: authors.Author{ID: id, Name: "name"}},
+			response:    authors.Author{ID: id, Name: "name"},
+			status:      http.StatusOK,
+		},
+		"Not found": {
+			mockService: &mockService{GetErr: authors.ErrAuthorNotFound},
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: authors.ErrAuthorNotFound.Error(),
+				Field:       "id",
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService)
+
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/authors/%s", id), nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusOK {
+				var a authors.Author
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &a))
+				assert.Equal(t, test.response, a)
+			} else {
+				var err errors.AppError
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &err))
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerGetAll(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		uri      string
+		response interface{}
+		status   int
+	}{
+		"Happy path": {
+			uri:      "/authors/",
+			response: authors.Authors{Authors: []authors.Author{{ID: id}}},
+			status:   http.StatusOK,
+		},
+		"Negative paging": {
+			uri: "/authors/?limit=0&offset=-1",
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "limit must be a positive number", Field: "limit"},
+				{Code: errors.BadRequest, Description: "offset must not be negative", Field: "offset"},
+			}},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, &mockService{GetAllResult: []authors.Author{{ID: id}}})
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusOK {
+				var al authors.Authors
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &al))
+				assert.Equal(t, test.response, al)
+			} else {
+				var errs errors.AppErrors
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &errs))
+				assert.Equal(t, test.response, errs)
+			}
+		})
+	}
+}
+
+func TestHandlerCreate(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		body     string
+		response interface{}
+		status   int
+	}{
+		"Happy path": {
+			body:     `{"name": "name", "bio": "bio"}`,
+			response: authors.Author{ID: id, Name: "name", Bio: "bio"},
+			status:   http.StatusCreated,
+		},
+		"Missing name": {
+			body: `{"bio": "bio"}`,
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: errors.Descriptions[errors.BadRequest],
+				Field:       "",
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, &mockService{CreateResult: authors.Author{ID: id, Name: "name", Bio: "bio"}})
+
+			req, err := http.NewRequest(http.MethodPost, "/authors/", strings.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Add("Content-Type", "application/json")
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusCreated {
+				var a authors.Author
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &a))
+				assert.Equal(t, test.response, a)
+			} else {
+				var err errors.AppError
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &err))
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerDelete(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]struct {
+		mockService authors.Service
+		status      int
+	}{
+		"Happy path": {
+			mockService: &mockService{},
+			status:      http.StatusNoContent,
+		},
+		"Not found": {
+			mockService: &mockService{DeleteErr: authors.ErrAuthorNotFound},
+			status:      http.StatusNotFound,
+		},
+		"Delete failure": {
+			mockService: &mockService{DeleteErr: authors.ErrAuthorDelete},
+			status:      http.StatusInternalServerError,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService)
+
+			req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/authors/%s", id), nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package comments
+
+import (
+	"context"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+// Repo defines the DB level interaction of comments
+type Repo interface {
+	Get(ctx context.Context, articleID, id string) (Comment, error)
+	GetAll(ctx context.Context, p ListParams) ([]Comment, error)
+	Create(ctx context.Context, articleID string, cc CommentCreate) (string, error)
+	Update(ctx context.Context, articleID, id string, cu CommentUpdate) error
+	Delete(ctx context.Context, articleID, id string) error
+}
+
+// Service defines the service level contract that other services
+// outside this package can use to interact with Comment resources
+type Service interface {
+	Get(ctx context.Context, articleID, id string) (Comment, error)
+	GetAll(ctx context.Context, p ListParams) ([]Comment, error)
+	Create(ctx context.Context, articleID string, cc CommentCreate) (Comment, error)
+	Update(ctx context.Context, articleID, id string, cu CommentUpdate) (Comment, error)
+	Delete(ctx context.Context, articleID, id string) error
+}
+
+// ArticleService is the part of articles.Service comments rely on to look up the article commented on
+type ArticleService interface {
+	Get(ctx context.Context, id string, includeDisabled bool, fields []string) (articles.Article, error)
+}
+
+type comment struct {
+	repo           Repo
+	articleService ArticleService
+}
+
+// New Service instance
+func New(repo Repo, as ArticleService) Service {
+	return &comment{repo, as}
+}
+
+// Get retrieves the comment once the article is known to be live
+func (s *comment) Get(ctx context.Context, articleID, id string) (Comment, error) {
+	if err := s.articleLive(ctx, articleID, ErrCommentQuery); err != nil {
+		return Comment{}, err
+	}
+	return s.repo.Get(ctx, articleID, id)
+}
+
+// GetAll retrieves a page of comments once the article is known to be live
+func (s *comment) GetAll(ctx context.Context, p ListParams) ([]Comment, error) {
+	if err := s.articleLive(ctx, p.ArticleID, ErrCommentQuery); err != nil {
+		return make([]Comment, 0), err
+	}
+	return s.repo.GetAll(ctx, p)
+}
+
+// Create passes of the comment to the repo and retrieves the newly created record
+func (s *comment) Create(ctx context.Context, articleID string, cc CommentCreate) (Comment, error) {
+	if err := s.articleLive(ctx, articleID, ErrCommentCreate); err != nil {
+		return Comment{}, err
+	}
+	id, err := s.repo.Create(ctx, articleID, cc)
+	if err != nil {
+		return Comment{}, err
+	}
+	return s.repo.Get(ctx, articleID, id)
+}
+
+// Update the requested resource and retrieve it
+func (s *comment) Update(ctx context.Context, articleID, id string, cu CommentUpdate) (Comment, error) {
+	if err := s.articleLive(ctx, articleID, ErrCommentUpdate); err != nil {
+		return Comment{}, err
+	}
+	if err := s.repo.Update(ctx, articleID, id, cu); err != nil {
+		return Comment{}, err
+	}
+	return s.repo.Get(ctx, articleID, id)
+}
+
+// Delete soft deletes the comment, its replies are kept
+func (s *comment) Delete(ctx context.Context, articleID, id string) error {
+	if err := s.articleLive(ctx, articleID, ErrCommentDelete); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, articleID, id)
+}
+
+// articleLive fails with ErrArticleNotFound unless the article exists, is not disabled and is published, so
+// comments are only read or made where the article itself can be read without opting in. failErr is returned
+// when the article cannot be looked up. Only its status is read.
+func (s *comment) articleLive(ctx context.Context, articleID string, failErr error) error {
+	ar, err := s.articleService.Get(ctx, articleID, false, []string{"status"})
+	if err == articles.ErrArticleNotFound {
+		return ErrArticleNotFound
+	}
+	if err != nil {
+		log.Warn(ctx, "unable to look up commented article id=%s: %s", articleID, err.Error())
+		return failErr
+	}
+	if ar.Status != articles.StatusPublished {
+		log.Info(ctx, "commented article id=%s is %s, not published", articleID, ar.Status)
+		return ErrArticleNotFound
+	}
+	return nil
+}
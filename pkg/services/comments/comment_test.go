@@ -0,0 +1,189 @@
+package comments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/articles"
+)
+
+type repoMock struct {
+	GetResult Comment
+	GetError  error
+
+	GetAllResult []Comment
+	GetAllError  error
+
+	CreateResult string
+	CreateError  error
+
+	UpdateError error
+
+	DeleteError error
+}
+
+func (r *repoMock) Get(ctx context.Context, articleID, id string) (Comment, error) {
+	return r.GetResult, r.GetError
+}
+
+func (r *repoMock) GetAll(ctx context.Context, p ListParams) ([]Comment, error) {
+	return r.GetAllResult, r.GetAllError
+}
+
+func (r *repoMock) Create(ctx context.Context, articleID string, cc CommentCreate) (string, error) {
+	return r.CreateResult, r.CreateError
+}
+
+func (r *repoMock) Update(ctx context.Context, articleID, id string, cu CommentUpdate) error {
+	return r.UpdateError
+}
+
+func (r *repoMock) Delete(ctx context.Context, articleID, id string) error {
+	return r.DeleteError
+}
+
+type articleServiceMock struct {
+	GetResult articles.Article
+	GetError  error
+	GetFields []string
+}
+
+func (s *articleServiceMock) Get(ctx context.Context, id string, includeDisabled bool, fields []string) (articles.Article, error) {
+	s.GetFields = fields
+	return s.GetResult, s.GetError
+}
+
+func TestServiceCreate(t *testing.T) {
+	articleID := uuid.New().String()
+	published := articles.Article{ID: articleID, Status: articles.StatusPublished}
+	id := uuid.New().String()
+	tests := map[string]struct {
+		repo     Repo
+		articles *articleServiceMock
+		result   Comment
+		err      error
+	}{
+		"Happy path": {
+			repo:     &repoMock{CreateResult: id, GetResult: Comment{ID: id, ArticleID: articleID, Body: "body"}},
+			articles: &articleServiceMock{GetResult: published},
+			result:   Comment{ID: id, ArticleID: articleID, Body: "body"},
+			err:      nil,
+		},
+		"Missing or disabled article": {
+			repo:     &repoMock{},
+			articles: &articleServiceMock{GetError: articles.ErrArticleNotFound},
+			result:   Comment{},
+			err:      ErrArticleNotFound,
+		},
+		"Unpublished article": {
+			repo:     &repoMock{CreateResult: id},
+			articles: &articleServiceMock{GetResult: articles.Article{ID: articleID, Status: articles.StatusArchived}},
+			result:   Comment{},
+			err:      ErrArticleNotFound,
+		},
+		"Article lookup error": {
+			repo:     &repoMock{},
+			articles: &articleServiceMock{GetError: articles.ErrArticleQuery},
+			result:   Comment{},
+			err:      ErrCommentCreate,
+		},
+		"Missing parent": {
+			repo:     &repoMock{CreateError: ErrParentNotFound},
+			articles: &articleServiceMock{GetResult: published},
+			result:   Comment{},
+			err:      ErrParentNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo, test.articles)
+			response, err := service.Create(context.Background(), articleID, CommentCreate{Body: "body"})
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
+
+func TestServiceUpdate(t *testing.T) {
+	articleID := uuid.New().String()
+	id := uuid.New().String()
+	tests := map[string]struct {
+		repo   Repo
+		result Comment
+		err    error
+	}{
+		"Happy path": {
+			repo:   &repoMock{GetResult: Comment{ID: id, ArticleID: articleID, Body: "new body"}},
+			result: Comment{ID: id, ArticleID: articleID, Body: "new body"},
+			err:    nil,
+		},
+		"Deleted comment": {
+			repo:   &repoMock{UpdateError: ErrCommentNotFound},
+			result: Comment{},
+			err:    ErrCommentNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo, &articleServiceMock{GetResult: articles.Article{ID: articleID, Status: articles.StatusPublished}})
+			response, err := service.Update(context.Background(), articleID, id, CommentUpdate{Body: "new body"})
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+		})
+	}
+}
+
+func TestServiceGetAll(t *testing.T) {
+	articleID := uuid.New().String()
+	published := articles.Article{ID: articleID, Status: articles.StatusPublished}
+	id := uuid.New().String()
+	tests := map[string]struct {
+		repo     Repo
+		articles *articleServiceMock
+		result   []Comment
+		err      error
+	}{
+		"Happy path": {
+			repo:     &repoMock{GetAllResult: []Comment{{ID: id, ArticleID: articleID}}},
+			articles: &articleServiceMock{GetResult: published},
+			result:   []Comment{{ID: id, ArticleID: articleID}},
+			err:      nil,
+		},
+		"Missing or disabled article": {
+			repo:     &repoMock{GetAllResult: []Comment{{ID: id, ArticleID: articleID}}},
+			articles: &articleServiceMock{GetError: articles.ErrArticleNotFound},
+			result:   []Comment{},
+			err:      ErrArticleNotFound,
+		},
+		"Unpublished article": {
+			repo:     &repoMock{GetAllResult: []Comment{{ID: id, ArticleID: articleID}}},
+			articles: &articleServiceMock{GetResult: articles.Article{ID: articleID, Status: articles.StatusDraft}},
+			result:   []Comment{},
+			err:      ErrArticleNotFound,
+		},
+		"Article lookup error": {
+			repo:     &repoMock{},
+			articles: &articleServiceMock{GetError: articles.ErrArticleQuery},
+			result:   []Comment{},
+			err:      ErrCommentQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			service := New(test.repo, test.articles)
+			response, err := service.GetAll(context.Background(), ListParams{ArticleID: articleID, Limit: 25})
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, response)
+			assert.Equal(t, []string{"status"}, test.articles.GetFields)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package comments
+
+import (
+	"errors"
+)
+
+var (
+	// ErrCommentNotFound ...
+	ErrCommentNotFound = errors.New("requested comment could not be found")
+
+	// ErrArticleNotFound ...
+	ErrArticleNotFound = errors.New("commented article could not be found")
+
+	// ErrParentNotFound ...
+	ErrParentNotFound = errors.New("comment replied to could not be found")
+
+	// ErrCommentQuery ...
+	ErrCommentQuery = errors.New("requested comments could not be retrieved base on the given criteria")
+
+	// ErrCommentCreate ...
+	ErrCommentCreate = errors.New("comment could not be created")
+
+	// ErrCommentUpdate ...
+	ErrCommentUpdate = errors.New("comment could not be updated")
+
+	// ErrCommentDelete ...
+	ErrCommentDelete = errors.New("comment could not be deleted")
+)
@@ -0,0 +1,44 @@
+package comments
+
+import "time"
+
+// Comment is the nominal object used for interacting with comments on articles.
+// Deleted comments are kept so that the replies to them stay in place, but their body is cleared.
+type Comment struct {
+	ID        string  `json:"id"`
+	ArticleID string  `json:"articleId"`
+	ParentID  *string `json:"parentId"`
+
+	Body       string `json:"body"`
+	ReplyCount int    `json:"replyCount"`
+
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// Comments is used to present a list of comments in a JSON response.
+type Comments struct {
+	Comments []Comment `json:"comments"`
+}
+
+// CommentCreate is the request body that is accepted when commenting on an article.
+// A comment with a ParentID is a reply to that comment.
+type CommentCreate struct {
+	Body     string  `json:"body" binding:"required"`
+	ParentID *string `json:"parentId" binding:"omitempty,uuid"`
+}
+
+// CommentUpdate is the request body that is accepted for updates to comments.
+type CommentUpdate struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// ListParams selects a page of the comments on an article. Without a ParentID the top level
+// comments are listed, otherwise the replies to the comment with that id.
+type ListParams struct {
+	ArticleID string
+	ParentID  string
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kott/go-service-example/pkg/services/comments"
+	"github.com/kott/go-service-example/pkg/utils/log"
+)
+
+// commentColumns are the columns scanned into a comments.Comment, in scan order.
+// The body of deleted comments is cleared, only live replies are counted.
+const commentColumns = `id, article_id, parent_id, CASE WHEN deleted_at IS NULL THEN body ELSE '' END AS body, ` +
+	`(SELECT count(*) FROM comments replies WHERE replies.parent_id = comments.id AND replies.deleted_at IS NULL) AS reply_count, ` +
+	`created_at, updated_at, deleted_at`
+
+const (
+	lockLiveParent      = `SELECT 1 FROM comments WHERE id = $1 AND article_id = $2 AND deleted_at IS NULL FOR SHARE`
+	selectComment       = `SELECT ` + commentColumns + ` FROM comments WHERE id = $1 AND article_id = $2`
+	selectTopComments   = `SELECT ` + commentColumns + ` FROM comments WHERE article_id = $1 AND parent_id IS NULL ORDER BY created_at, id LIMIT $2 OFFSET $3`
+	selectReplyComments = `SELECT ` + commentColumns + ` FROM comments WHERE article_id = $1 AND parent_id = $2 ORDER BY created_at, id LIMIT $3 OFFSET $4`
+	insertComment       = `INSERT INTO comments (article_id, parent_id, body, created_at, updated_at) VALUES ($1, $2, $3, now(), now()) RETURNING id`
+	updateComment       = `UPDATE comments SET body = $1, updated_at = now() WHERE id = $2 AND article_id = $3 AND deleted_at IS NULL`
+	deleteComment       = `UPDATE comments SET deleted_at = now(), updated_at = now() WHERE id = $1 AND article_id = $2 AND deleted_at IS NULL`
+)
+
+type commentRepo struct {
+	DB *sql.DB
+}
+
+// New creates an instance of the commentRepo.
+func New(conn *sql.DB) comments.Repo {
+	return &commentRepo{conn}
+}
+
+// commentDest returns the scan destinations of commentColumns for c
+func commentDest(c *comments.Comment) []interface{} {
+	return []interface{}{&c.ID, &c.ArticleID, &c.ParentID, &c.Body, &c.ReplyCount, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt}
+}
+
+// Get retrieves the comment with the given id on the article, deleted comments included
+func (r *commentRepo) Get(ctx context.Context, articleID, id string) (comments.Comment, error) {
+	var c comments.Comment
+	if err := r.DB.QueryRow(selectComment, id, articleID).Scan(commentDest(&c)...); err != nil {
+		log.Info(ctx, "select comment error: %s", err.Error())
+		return c, comments.ErrCommentNotFound
+	}
+
+	return c, nil
+}
+
+// GetAll retrieves a page of the top level comments on the article, or of the replies to a
+// comment, oldest first
+func (r *commentRepo) GetAll(ctx context.Context, p comments.ListParams) ([]comments.Comment, error) {
+	cl := make([]comments.Comment, 0)
+
+	var rows *sql.Rows
+	var err error
+	if p.ParentID == "" {
+		rows, err = r.DB.Query(selectTopComments, p.ArticleID, p.Limit, p.Offset)
+	} else {
+		rows, err = r.DB.Query(selectReplyComments, p.ArticleID, p.ParentID, p.Limit, p.Offset)
+	}
+	if err != nil {
+		log.Warn(ctx, "unable to query db: %s", err.Error())
+		return cl, comments.ErrCommentQuery
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c comments.Comment
+		if err := rows.Scan(commentDest(&c)...); err != nil {
+			log.Error(ctx, "unable to scan db rows: %s", err.Error())
+			return cl, comments.ErrCommentQuery
+		}
+
+		cl = append(cl, c)
+	}
+
+	return cl, nil
+}
+
+// Create adds the comment to the article. The comment replied to is locked until the comment is in
+// place so it cannot be deleted in the meantime.
+func (r *commentRepo) Create(ctx context.Context, articleID string, cc comments.CommentCreate) (string, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error(ctx, "unable to begin transaction: %s", err.Error())
+		return "", comments.ErrCommentCreate
+	}
+	defer tx.Rollback()
+
+	if cc.ParentID != nil {
+		var one int
+		if err := tx.QueryRow(lockLiveParent, *cc.ParentID, articleID).Scan(&one); err != nil {
+			log.Info(ctx, "select parent comment error: %s", err.Error())
+			return "", comments.ErrParentNotFound
+		}
+	}
+
+	var id string
+	if err := tx.QueryRow(insertComment, articleID, cc.ParentID, cc.Body).Scan(&id); err != nil {
+		log.Error(ctx, "unable to create comment: %s", err.Error())
+		return "", comments.ErrCommentCreate
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(ctx, "unable to commit transaction: %s", err.Error())
+		return "", comments.ErrCommentCreate
+	}
+
+	log.Info(ctx, "created comment with id=%s on article id=%s", id, articleID)
+	return id, nil
+}
+
+// Update sets the body of a comment which has not been deleted
+func (r *commentRepo) Update(ctx context.Context, articleID, id string, cu comments.CommentUpdate) error {
+	return r.exec(ctx, comments.ErrCommentUpdate, updateComment, cu.Body, id, articleID)
+}
+
+// Delete marks the comment as deleted, its replies are left in place
+func (r *commentRepo) Delete(ctx context.Context, articleID, id string) error {
+	if err := r.exec(ctx, comments.ErrCommentDelete, deleteComment, id, articleID); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "deleted comment with id=%s", id)
+	return nil
+}
+
+// exec runs a statement which modifies a single comment, failing with ErrCommentNotFound when
+// no comment was modified
+func (r *commentRepo) exec(ctx context.Context, failErr error, query string, args ...interface{}) error {
+	res, err := r.DB.Exec(query, args...)
+	if err != nil {
+		log.Error(ctx, "unable to modify comment: %s", err.Error())
+		return failErr
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Error(ctx, "unable to modify comment: %s", err.Error())
+		return failErr
+	}
+	if n == 0 {
+		return comments.ErrCommentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/services/comments"
+)
+
+var commentRowColumns = []string{"id", "article_id", "parent_id", "body", "reply_count", "created_at", "updated_at", "deleted_at"}
+
+func TestCommentRepoGetAll(t *testing.T) {
+	articleID := uuid.New().String()
+	parentID := uuid.New().String()
+	id := uuid.New().String()
+	now := time.Now()
+
+	tests := map[string]struct {
+		input            comments.ListParams
+		expectQuery      string
+		expectArgs       []driver.Value
+		expectQueryError error
+		expect           []comments.Comment
+		err              error
+	}{
+		"Top level": {
+			input:       comments.ListParams{ArticleID: articleID, Limit: 25},
+			expectQuery: selectTopComments,
+			expectArgs:  []driver.Value{articleID, 25, 0},
+			expect:      []comments.Comment{{ID: id, ArticleID: articleID, Body: "body", ReplyCount: 2, CreatedAt: now, UpdatedAt: now}},
+		},
+		"Replies": {
+			input:       comments.ListParams{ArticleID: articleID, ParentID: parentID, Limit: 10, Offset: 10},
+			expectQuery: selectReplyComments,
+			expectArgs:  []driver.Value{articleID, parentID, 10, 10},
+			expect:      []comments.Comment{{ID: id, ArticleID: articleID, Body: "body", ReplyCount: 2, CreatedAt: now, UpdatedAt: now}},
+		},
+		"Query error": {
+			input:            comments.ListParams{ArticleID: articleID, Limit: 25},
+			expectQuery:      selectTopComments,
+			expectArgs:       []driver.Value{articleID, 25, 0},
+			expectQueryError: errors.New("some-db-error"),
+			expect:           []comments.Comment{},
+			err:              comments.ErrCommentQuery,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectQuery(regexp.QuoteMeta(test.expectQuery)).
+				WithArgs(test.expectArgs...).
+				WillReturnError(test.expectQueryError).
+				WillReturnRows(sqlmock.NewRows(commentRowColumns).AddRow(id, articleID, nil, "body", 2, now, now, nil))
+
+			repo := New(db)
+			response, err := repo.GetAll(context.Background(), test.input)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expect, response)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCommentRepoCreate(t *testing.T) {
+	articleID := uuid.New().String()
+	parentID := uuid.New().String()
+	id := uuid.New().String()
+
+	tests := map[string]struct {
+		input      comments.CommentCreate
+		parentLive bool
+		expectID   string
+		err        error
+	}{
+		"Top level": {
+			input:    comments.CommentCreate{Body: "body"},
+			expectID: id,
+		},
+		"Reply": {
+			input:      comments.CommentCreate{Body: "body", ParentID: &parentID},
+			parentLive: true,
+			expectID:   id,
+		},
+		"Missing or deleted parent": {
+			input: comments.CommentCreate{Body: "body", ParentID: &parentID},
+			err:   comments.ErrParentNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectBegin()
+			if test.input.ParentID != nil {
+				parent := sqlmock.NewRows([]string{"?column?"})
+				if test.parentLive {
+					parent.AddRow(1)
+				}
+				mock.ExpectQuery(regexp.QuoteMeta(lockLiveParent)).WithArgs(parentID, articleID).WillReturnRows(parent)
+			}
+			if test.err == nil {
+				mock.ExpectQuery(regexp.QuoteMeta(insertComment)).
+					WithArgs(articleID, test.input.ParentID, "body").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			repo := New(db)
+			response, err := repo.Create(context.Background(), articleID, test.input)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expectID, response)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCommentRepoDelete(t *testing.T) {
+	articleID := uuid.New().String()
+	id := uuid.New().String()
+
+	tests := map[string]struct {
+		expectExecResult driver.Result
+		expectExecError  error
+		err              error
+	}{
+		"Happy path": {
+			expectExecResult: sqlmock.NewResult(0, 1),
+			err:              nil,
+		},
+		"Missing or already deleted": {
+			expectExecResult: sqlmock.NewResult(0, 0),
+			err:              comments.ErrCommentNotFound,
+		},
+		"Delete error": {
+			expectExecError: errors.New("some-db-error"),
+			err:             comments.ErrCommentDelete,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(deleteComment)).WithArgs(id, articleID).WillReturnError(test.expectExecError).WillReturnResult(test.expectExecResult)
+
+			repo := New(db)
+			err := repo.Delete(context.Background(), articleID, id)
+
+			assert.Equal(t, test.err, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
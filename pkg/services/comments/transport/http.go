@@ -0,0 +1,219 @@
+package transport
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/articles"
+	articlestore "github.com/kott/go-service-example/pkg/services/articles/store"
+	"github.com/kott/go-service-example/pkg/services/comments"
+	"github.com/kott/go-service-example/pkg/services/comments/store"
+	"github.com/kott/go-service-example/pkg/utils/context"
+	"github.com/kott/go-service-example/pkg/utils/log"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
+	"github.com/kott/go-service-example/pkg/utils/paging"
+)
+
+type handler struct {
+	CommentService comments.Service
+}
+
+// Activate sets all the services required for comments and registers all the endpoints with the engine.
+func Activate(router *gin.Engine, db *sql.DB) {
+	articleService := articles.New(articlestore.New(db))
+	commentService := comments.New(store.New(db), articleService)
+	newHandler(router, commentService)
+}
+
+func newHandler(router *gin.Engine, cs comments.Service) {
+	h := handler{
+		CommentService: cs,
+	}
+
+	router.GET("/articles/:id/comments", h.GetAll)
+	router.POST("/articles/:id/comments", h.Create)
+	router.GET("/articles/:id/comments/:commentId", h.Get)
+	router.PUT("/articles/:id/comments/:commentId", h.Update)
+	router.DELETE("/articles/:id/comments/:commentId", h.Delete)
+}
+
+func (h *handler) Get(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+	articleID, id, err := commentIDs(c)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	log.Info(ctx, "retrieving comment id=%s on article id=%s", id, articleID)
+	comment, err := h.CommentService.Get(ctx, articleID, id)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, comment)
+}
+
+func (h *handler) GetAll(c *gin.Context) {
+	var q struct {
+		paging.Query
+		ParentID string `form:"parentId"`
+	}
+
+	ctx := context.GetReqCtx(c)
+	if err := c.BindQuery(&q); err != nil {
+		log.Info(ctx, "query parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	errs := q.Validate()
+	if _, err := uuid.Parse(q.ParentID); q.ParentID != "" && err != nil {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "parentId must be a comment id", Field: "parentId"})
+	}
+	if len(errs) > 0 {
+		log.Info(ctx, "invalid list query: %v", errs)
+		negotiate.Render(c, http.StatusBadRequest, errors.AppErrors{Errors: errs})
+		return
+	}
+
+	articleID := c.Param("id")
+	if _, err := uuid.Parse(articleID); err != nil {
+		status, appErr := handleError(comments.ErrArticleNotFound)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	log.Info(ctx, "retrieving comments on article id=%s: parent=%q offset=%d limit=%d", articleID, q.ParentID, q.Offset, q.Limit)
+	cl, err := h.CommentService.GetAll(ctx, comments.ListParams{
+		ArticleID: articleID,
+		ParentID:  q.ParentID,
+		Limit:     q.Limit,
+		Offset:    q.Offset,
+	})
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, comments.Comments{Comments: cl})
+}
+
+func (h *handler) Create(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+
+	var cc comments.CommentCreate
+	if err := c.ShouldBindJSON(&cc); err != nil {
+		log.Info(ctx, "request parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	articleID := c.Param("id")
+	if _, err := uuid.Parse(articleID); err != nil {
+		status, appErr := handleError(comments.ErrArticleNotFound)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	log.Info(ctx, "creating comment on article id=%s: %v", articleID, cc)
+	comment, err := h.CommentService.Create(ctx, articleID, cc)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusCreated, comment)
+}
+
+func (h *handler) Update(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+
+	var cu comments.CommentUpdate
+	if err := c.ShouldBindJSON(&cu); err != nil {
+		log.Info(ctx, "request parse error: %s", err.Error())
+		negotiate.Render(c, http.StatusBadRequest,
+			errors.NewAppError(errors.BadRequest, errors.Descriptions[errors.BadRequest], ""))
+		return
+	}
+
+	articleID, id, err := commentIDs(c)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	log.Info(ctx, "updating comment %s on article id=%s with %v", id, articleID, cu)
+	comment, err := h.CommentService.Update(ctx, articleID, id, cu)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	negotiate.Render(c, http.StatusOK, comment)
+}
+
+func (h *handler) Delete(c *gin.Context) {
+	ctx := context.GetReqCtx(c)
+	articleID, id, err := commentIDs(c)
+	if err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	log.Info(ctx, "deleting comment %s on article id=%s", id, articleID)
+	if err := h.CommentService.Delete(ctx, articleID, id); err != nil {
+		status, appErr := handleError(err)
+		negotiate.Render(c, status, appErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// commentIDs reads the article and comment ids from the path. Ids which are not UUIDs cannot
+// refer to anything, so they are reported as not found rather than left for the database to reject.
+func commentIDs(c *gin.Context) (articleID, id string, err error) {
+	articleID, id = c.Param("id"), c.Param("commentId")
+	if _, err := uuid.Parse(articleID); err != nil {
+		return "", "", comments.ErrArticleNotFound
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return "", "", comments.ErrCommentNotFound
+	}
+	return articleID, id, nil
+}
+
+// handleError allows us to map errors defined internally to appropriate HTTP error codes and JSON responses
+func handleError(e error) (int, error) {
+	switch e {
+	case comments.ErrArticleNotFound:
+		return http.StatusNotFound, errors.NewAppError(errors.NotFound, e.Error(), "articleId")
+	case comments.ErrCommentNotFound:
+		return http.StatusNotFound, errors.NewAppError(errors.NotFound, e.Error(), "commentId")
+	case comments.ErrParentNotFound:
+		return http.StatusNotFound, errors.NewAppError(errors.NotFound, e.Error(), "parentId")
+	case comments.ErrCommentUpdate:
+		fallthrough
+	case comments.ErrCommentCreate:
+		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, "unable to create/update comment", "")
+	case comments.ErrCommentDelete:
+		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, "unable to delete comment", "")
+	default:
+		return http.StatusInternalServerError, errors.NewAppError(errors.InternalServerError, e.Error(), "unknown")
+	}
+}
@@ -0,0 +1,269 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/services/comments"
+	"github.com/kott/go-service-example/pkg/utils/paging"
+)
+
+type mockService struct {
+	GetResult    comments.Comment
+	GetErr       error
+	GetAllResult []comments.Comment
+	GetAllErr    error
+	GetAllParams comments.ListParams
+	CreateResult comments.Comment
+	CreateErr    error
+	UpdateResult comments.Comment
+	UpdateErr    error
+	DeleteErr    error
+}
+
+func (s *mockService) Get(ctx context.Context, articleID, id string) (comments.Comment, error) {
+	return s.GetResult, s.GetErr
+}
+
+func (s *mockService) GetAll(ctx context.Context, p comments.ListParams) ([]comments.Comment, error) {
+	s.GetAllParams = p
+	return s.GetAllResult, s.GetAllErr
+}
+
+func (s *mockService) Create(ctx context.Context, articleID string, cc comments.CommentCreate) (comments.Comment, error) {
+	return s.CreateResult, s.CreateErr
+}
+
+func (s *mockService) Update(ctx context.Context, articleID, id string, cu comments.CommentUpdate) (comments.Comment, error) {
+	return s.UpdateResult, s.UpdateErr
+}
+
+func (s *mockService) Delete(ctx context.Context, articleID, id string) error {
+	return s.DeleteErr
+}
+
+func TestHandlerGetAll(t *testing.T) {
+	articleID := uuid.New().String()
+	parentID := uuid.New().String()
+	id := uuid.New().String()
+	tests := map[string]struct {
+		uri         string
+		mockService *mockService
+		params      comments.ListParams
+		response    interface{}
+		status      int
+	}{
+		"Top level": {
+			uri:         fmt.Sprintf("/articles/%s/comments", articleID),
+			mockService: &mockService{GetAllResult: []comments.Comment{{ID: id, ArticleID: articleID}}},
+			params:      comments.ListParams{ArticleID: articleID, Limit: 25},
+			response:    comments.Comments{Comments: []comments.Comment{{ID: id, ArticleID: articleID}}},
+			status:      http.StatusOK,
+		},
+		"Replies": {
+			uri:         fmt.Sprintf("/articles/%s/comments?parentId=%s&limit=500&offset=5", articleID, parentID),
+			mockService: &mockService{GetAllResult: []comments.Comment{{ID: id, ArticleID: articleID, ParentID: &parentID}}},
+			params:      comments.ListParams{ArticleID: articleID, ParentID: parentID, Limit: paging.MaxLimit, Offset: 5},
+			response:    comments.Comments{Comments: []comments.Comment{{ID: id, ArticleID: articleID, ParentID: &parentID}}},
+			status:      http.StatusOK,
+		},
+		"Invalid query": {
+			uri:         fmt.Sprintf("/articles/%s/comments?parentId=abc&limit=0", articleID),
+			mockService: &mockService{},
+			response: errors.AppErrors{Errors: []errors.AppError{
+				{Code: errors.BadRequest, Description: "limit must be a positive number", Field: "limit"},
+				{Code: errors.BadRequest, Description: "parentId must be a comment id", Field: "parentId"},
+			}},
+			status: http.StatusBadRequest,
+		},
+		"Missing article": {
+			uri:         fmt.Sprintf("/articles/%s/comments", articleID),
+			mockService: &mockService{GetAllErr: comments.ErrArticleNotFound},
+			params:      comments.ListParams{ArticleID: articleID, Limit: 25},
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: comments.ErrArticleNotFound.Error(),
+				Field:       "articleId",
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService)
+
+			req, err := http.NewRequest(http.MethodGet, test.uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+			assert.Equal(t, test.params, test.mockService.GetAllParams)
+
+			switch expected := test.response.(type) {
+			case comments.Comments:
+				var cl comments.Comments
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &cl))
+				assert.Equal(t, expected, cl)
+			case errors.AppErrors:
+				var errs errors.AppErrors
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &errs))
+				assert.Equal(t, expected, errs)
+			default:
+				var err errors.AppError
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &err))
+				assert.Equal(t, expected, err)
+			}
+		})
+	}
+}
+
+func TestHandlerCreate(t *testing.T) {
+	articleID := uuid.New().String()
+	id := uuid.New().String()
+	tests := map[string]struct {
+		articleID   string
+		body        string
+		mockService comments.Service
+		response    interface{}
+		status      int
+	}{
+		"Happy path": {
+			articleID:   articleID,
+			body:        `{"body": "body"}`,
+			mockService: &mockService{CreateResult: comments.Comment{ID: id, ArticleID: articleID, Body: "body"}},
+			response:    comments.Comment{ID: id, ArticleID: articleID, Body: "body"},
+			status:      http.StatusCreated,
+		},
+		"Missing body": {
+			articleID:   articleID,
+			body:        `{"parentId": "` + id + `"}`,
+			mockService: &mockService{},
+			response: errors.AppError{
+				Code:        errors.BadRequest,
+				Description: errors.Descriptions[errors.BadRequest],
+				Field:       "",
+			},
+			status: http.StatusBadRequest,
+		},
+		"Missing or disabled article": {
+			articleID:   articleID,
+			body:        `{"body": "body"}`,
+			mockService: &mockService{CreateErr: comments.ErrArticleNotFound},
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: comments.ErrArticleNotFound.Error(),
+				Field:       "articleId",
+			},
+			status: http.StatusNotFound,
+		},
+		"Malformed article id": {
+			articleID:   "not-a-uuid",
+			body:        `{"body": "body"}`,
+			mockService: &mockService{},
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: comments.ErrArticleNotFound.Error(),
+				Field:       "articleId",
+			},
+			status: http.StatusNotFound,
+		},
+		"Missing parent": {
+			articleID:   articleID,
+			body:        `{"body": "body", "parentId": "` + id + `"}`,
+			mockService: &mockService{CreateErr: comments.ErrParentNotFound},
+			response: errors.AppError{
+				Code:        errors.NotFound,
+				Description: comments.ErrParentNotFound.Error(),
+				Field:       "parentId",
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService)
+
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/articles/%s/comments", test.articleID), strings.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Add("Content-Type", "application/json")
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+
+			if test.status == http.StatusCreated {
+				var cm comments.Comment
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &cm))
+				assert.Equal(t, test.response, cm)
+			} else {
+				var err errors.AppError
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &err))
+				assert.Equal(t, test.response, err)
+			}
+		})
+	}
+}
+
+func TestHandlerDelete(t *testing.T) {
+	articleID := uuid.New().String()
+	id := uuid.New().String()
+	tests := map[string]struct {
+		commentID   string
+		mockService comments.Service
+		status      int
+	}{
+		"Happy path": {
+			commentID:   id,
+			mockService: &mockService{},
+			status:      http.StatusNoContent,
+		},
+		"Already deleted": {
+			commentID:   id,
+			mockService: &mockService{DeleteErr: comments.ErrCommentNotFound},
+			status:      http.StatusNotFound,
+		},
+		"Malformed comment id": {
+			commentID:   "not-a-uuid",
+			mockService: &mockService{},
+			status:      http.StatusNotFound,
+		},
+		"Delete failure": {
+			commentID:   id,
+			mockService: &mockService{DeleteErr: comments.ErrCommentDelete},
+			status:      http.StatusInternalServerError,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			newHandler(router, test.mockService)
+
+			req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/articles/%s/comments/%s", articleID, test.commentID), nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.status, response.Code)
+		})
+	}
+}
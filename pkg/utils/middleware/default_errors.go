@@ -6,20 +6,21 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
 )
 
-// NoRoute sets default 404 errors to a JSON AppError
+// NoRoute sets default 404 errors to an AppError in the negotiated format
 func NoRoute() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, errors.NewAppError(errors.NotFound,
+		negotiate.Abort(c, http.StatusNotFound, errors.NewAppError(errors.NotFound,
 			errors.Descriptions[errors.NotFound], "nil"))
 	}
 }
 
-// NoMethod sets default 405 errors to a JSON AppError
+// NoMethod sets default 405 errors to an AppError in the negotiated format
 func NoMethod() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusMethodNotAllowed, errors.NewAppError(errors.MethodNotAllowed,
+		negotiate.Abort(c, http.StatusMethodNotAllowed, errors.NewAppError(errors.MethodNotAllowed,
 			errors.Descriptions[errors.MethodNotAllowed], "nil"))
 	}
 }
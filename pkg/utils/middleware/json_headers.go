@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
 )
 
 const (
@@ -16,6 +17,7 @@ const (
 var allowedContentTypes = map[string]interface{}{
 	"application/json":               nil,
 	"application/json;charset=utf-8": nil,
+	"application/merge-patch+json":   nil,
 }
 
 // JSONResponseHeader sets the content-type of responses to application/json.
@@ -26,22 +28,44 @@ func JSONResponseHeader() gin.HandlerFunc {
 	}
 }
 
-// ForceJSON will require the request to have a json content-type
-func ForceJSON() gin.HandlerFunc {
+// RouteContentTypes lets the route registered with Path accept request bodies of
+// the given content types, in addition to the json content-types every route accepts.
+type RouteContentTypes struct {
+	Path         string
+	ContentTypes []string
+}
+
+// ForceJSON will require the request to have a json content-type, or one of the content-types allowed for its route
+func ForceJSON(routes ...RouteContentTypes) gin.HandlerFunc {
+	routeContentTypes := make(map[string]map[string]bool)
+	for _, r := range routes {
+		if routeContentTypes[r.Path] == nil {
+			routeContentTypes[r.Path] = make(map[string]bool)
+		}
+		for _, ct := range r.ContentTypes {
+			routeContentTypes[r.Path][strings.ToLower(ct)] = true
+		}
+	}
+
 	return func(c *gin.Context) {
 		header := strings.TrimSpace(strings.ToLower(c.ContentType()))
-		if _, ok := allowedContentTypes[header]; !ok && containsBody(c.Request.Method, c.Request.ContentLength) {
-			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, errors.NewAppError(errors.UnsupportedMediaType,
+		_, ok := allowedContentTypes[header]
+		if !ok {
+			ok = routeContentTypes[c.FullPath()][header]
+		}
+		if !ok && containsBody(c.Request.Method, c.Request.ContentLength) {
+			negotiate.Abort(c, http.StatusUnsupportedMediaType, errors.NewAppError(errors.UnsupportedMediaType,
 				errors.Descriptions[errors.UnsupportedMediaType], "nil"))
 		}
 		c.Next()
 	}
 }
 
+// containsBody reports whether a request may carry a body. A streamed body has an unknown (negative) length.
 func containsBody(method string, contentLength int64) bool {
 	switch method {
 	case http.MethodPost, http.MethodPut, http.MethodPatch:
-		return contentLength > 0
+		return contentLength != 0
 	default:
 		return false
 	}
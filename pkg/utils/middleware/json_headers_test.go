@@ -64,6 +64,26 @@ func TestForceJSONPass(t *testing.T) {
 	assert.Equal(t, expectedResponse, string(rr.Body.Bytes()))
 }
 
+func TestForceJSONMergePatch(t *testing.T) {
+	s := gin.New()
+	s.Use(ForceJSON())
+	s.PATCH("/", func(c *gin.Context) {
+		c.JSON(200, struct{}{})
+	})
+
+	rr := httptest.NewRecorder()
+	r, err := http.NewRequest("PATCH", "/", strings.NewReader("{}"))
+	r.Header = map[string][]string{
+		"Content-Type": {"application/merge-patch+json"},
+	}
+	require.NoError(t, err)
+
+	s.ServeHTTP(rr, r)
+	expectedResponse := `{}`
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, expectedResponse, string(rr.Body.Bytes()))
+}
+
 func TestForceJSONGet(t *testing.T) {
 	s := gin.New()
 	s.Use(ForceJSON())
@@ -80,3 +100,50 @@ func TestForceJSONGet(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, expectedResponse, string(rr.Body.Bytes()))
 }
+
+func TestForceJSONRouteContentTypes(t *testing.T) {
+	s := gin.New()
+	s.Use(ForceJSON(RouteContentTypes{Path: "/import/:kind", ContentTypes: []string{"text/csv"}}))
+	s.POST("/import/:kind", func(c *gin.Context) {
+		c.JSON(201, struct{}{})
+	})
+	s.POST("/other", func(c *gin.Context) {
+		c.JSON(201, struct{}{})
+	})
+
+	tests := map[string]struct {
+		path   string
+		status int
+	}{
+		"Allowed on route":     {path: "/import/articles", status: http.StatusCreated},
+		"Not allowed on other": {path: "/other", status: http.StatusUnsupportedMediaType},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			r, err := http.NewRequest("POST", test.path, strings.NewReader("title,body\n"))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "text/csv; charset=utf-8")
+
+			s.ServeHTTP(rr, r)
+			assert.Equal(t, test.status, rr.Code)
+		})
+	}
+}
+
+func TestForceJSONStreamedBody(t *testing.T) {
+	s := gin.New()
+	s.Use(ForceJSON())
+	s.POST("/", func(c *gin.Context) {
+		c.Status(201)
+	})
+
+	rr := httptest.NewRecorder()
+	r, err := http.NewRequest("POST", "/", strings.NewReader("{}"))
+	require.NoError(t, err)
+	r.ContentLength = -1
+
+	s.ServeHTTP(rr, r)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+}
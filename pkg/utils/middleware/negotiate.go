@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kott/go-service-example/pkg/errors"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
+)
+
+// Negotiate picks the format of the response from the Accept header of the request, out of negotiate.Formats and
+// the content types allowed for its route. A request accepting none of them is refused with a 406, whose body is
+// JSON since the client accepts nothing else we could write.
+func Negotiate(routes ...RouteContentTypes) gin.HandlerFunc {
+	routeFormats := make(map[string][]string)
+	for _, r := range routes {
+		formats := routeFormats[r.Path]
+		if formats == nil {
+			formats = append(formats, negotiate.Formats...)
+		}
+		for _, ct := range r.ContentTypes {
+			formats = append(formats, strings.ToLower(ct))
+		}
+		routeFormats[r.Path] = formats
+	}
+
+	return func(c *gin.Context) {
+		formats, ok := routeFormats[c.FullPath()]
+		if !ok {
+			formats = negotiate.Formats
+		}
+
+		c.Header("Vary", "Accept")
+		format, ok := negotiate.Select(c.GetHeader("Accept"), formats)
+		if !ok {
+			negotiate.SetFormat(c, negotiate.MIMEJSON)
+			negotiate.Abort(c, http.StatusNotAcceptable, errors.NewAppError(errors.NotAcceptable,
+				errors.Descriptions[errors.NotAcceptable], "Accept"))
+			return
+		}
+		negotiate.SetFormat(c, format)
+		c.Next()
+	}
+}
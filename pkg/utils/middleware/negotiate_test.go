@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]struct {
+		path     string
+		accept   string
+		status   int
+		format   string
+		response string
+	}{
+		"No Accept header": {
+			path:   "/",
+			status: http.StatusOK,
+			format: negotiate.MIMEJSON,
+		},
+		"Supported format": {
+			path:   "/",
+			accept: "application/x-yaml",
+			status: http.StatusOK,
+			format: negotiate.MIMEYAML,
+		},
+		"Route content type": {
+			path:   "/export",
+			accept: "text/csv",
+			status: http.StatusOK,
+			format: "text/csv",
+		},
+		"Route content type elsewhere": {
+			path:     "/",
+			accept:   "text/csv",
+			status:   http.StatusNotAcceptable,
+			response: `{"code":"NOT_ACCEPTABLE","description":"The server cannot respond in any of the media types accepted by the request.","field":"Accept"}`,
+		},
+		"Unsupported format": {
+			path:     "/export",
+			accept:   "text/html",
+			status:   http.StatusNotAcceptable,
+			response: `{"code":"NOT_ACCEPTABLE","description":"The server cannot respond in any of the media types accepted by the request.","field":"Accept"}`,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var format string
+			s := gin.New()
+			s.Use(Negotiate(RouteContentTypes{Path: "/export", ContentTypes: []string{"text/csv"}}))
+			handler := func(c *gin.Context) {
+				format = negotiate.GetFormat(c)
+				c.Status(http.StatusOK)
+			}
+			s.GET("/", handler)
+			s.GET("/export", handler)
+
+			rr := httptest.NewRecorder()
+			r, err := http.NewRequest(http.MethodGet, test.path, nil)
+			require.NoError(t, err)
+			r.Header.Set("Accept", test.accept)
+
+			s.ServeHTTP(rr, r)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+			assert.Equal(t, test.format, format)
+			assert.Equal(t, test.response, rr.Body.String())
+		})
+	}
+}
+
+func TestNegotiateErrorBodies(t *testing.T) {
+	s := gin.New()
+	s.Use(Negotiate())
+	s.Use(Recover())
+	s.NoRoute(NoRoute())
+	s.NoMethod(NoMethod())
+	s.HandleMethodNotAllowed = true
+	s.GET("/panic", func(c *gin.Context) {
+		panic("something bad happened")
+	})
+
+	tests := map[string]struct {
+		method   string
+		path     string
+		accept   string
+		status   int
+		response string
+	}{
+		"No route as XML": {
+			method: http.MethodGet,
+			path:   "/does-not-exist",
+			accept: "application/xml",
+			status: http.StatusNotFound,
+			response: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><code>NOT_FOUND</code><description>Resource does not exist.</description><field>nil</field></response>`,
+		},
+		"No method as YAML": {
+			method:   http.MethodPost,
+			path:     "/panic",
+			accept:   "application/yaml",
+			status:   http.StatusMethodNotAllowed,
+			response: "code: METHOD_NOT_ALLOWED\ndescription: Method is not allowed for this resource.\nfield: nil\n",
+		},
+		"Panic as YAML": {
+			method:   http.MethodGet,
+			path:     "/panic",
+			accept:   "text/yaml",
+			status:   http.StatusInternalServerError,
+			response: "code: INTERNAL_SERVER_ERROR\ndescription: Internal server error.\nfield: \"\"\n",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			r, err := http.NewRequest(test.method, test.path, nil)
+			require.NoError(t, err)
+			r.Header.Set("Accept", test.accept)
+
+			s.ServeHTTP(rr, r)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.response, rr.Body.String())
+		})
+	}
+}
@@ -10,10 +10,12 @@ import (
 	"github.com/kott/go-service-example/pkg/errors"
 	rcontext "github.com/kott/go-service-example/pkg/utils/context"
 	"github.com/kott/go-service-example/pkg/utils/log"
+	"github.com/kott/go-service-example/pkg/utils/negotiate"
 )
 
 // Recover is a middleware that recovers from panics, logs the panic (and a
-// backtrace) using wallet-api logger, and returns a HTTP 500 status.
+// backtrace) using wallet-api logger, and returns a HTTP 500 status
+// with an AppError in the negotiated format.
 func Recover() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := rcontext.GetReqCtx(c)
@@ -22,7 +24,7 @@ func Recover() gin.HandlerFunc {
 				logPanic(ctx, r)
 				err := errors.NewAppError(errors.InternalServerError,
 					errors.Descriptions[errors.InternalServerError], "")
-				c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+				negotiate.Abort(c, http.StatusInternalServerError, err)
 			}
 		}()
 		c.Next()
@@ -1,8 +1,6 @@
 package middleware
 
 import (
-	"context"
-
 	"github.com/gin-gonic/gin"
 
 	rcontext "github.com/kott/go-service-example/pkg/utils/context"
@@ -17,7 +15,8 @@ func PersistContext() gin.HandlerFunc {
 		reqID := currentReqID(c)
 		ctxLogger := log.New().WithField("reqID", reqID)
 
-		ctx := context.Background()
+		// building on the context of the request lets handlers notice that the client has gone away
+		ctx := c.Request.Context()
 		ctx = rcontext.SetRequestLogger(ctx, ctxLogger)
 		ctx = rcontext.SetReqID(ctx, reqID)
 		rcontext.SetReqCtx(ctx, c)
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,6 +10,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	rcontext "github.com/kott/go-service-example/pkg/utils/context"
 )
 
 func TestPersistContext(t *testing.T) {
@@ -43,3 +46,22 @@ func TestPersistContextNoReqID(t *testing.T) {
 	hReqID := rr.Header()["X-Request-Id"][0]
 	assert.NotEmpty(t, hReqID)
 }
+
+func TestPersistContextCancelled(t *testing.T) {
+	var reqErr error
+	s := gin.New()
+	s.Use(PersistContext())
+	s.GET("/", func(c *gin.Context) {
+		reqErr = rcontext.GetReqCtx(c).Err()
+		c.JSON(200, struct{}{})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rr := httptest.NewRecorder()
+	r, err := http.NewRequestWithContext(ctx, "GET", "/", nil)
+	require.NoError(t, err)
+	s.ServeHTTP(rr, r)
+
+	assert.Equal(t, context.Canceled, reqErr)
+}
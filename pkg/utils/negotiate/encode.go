@@ -0,0 +1,170 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	xmlContentType  = MIMEXML + "; charset=utf-8"
+	yamlContentType = MIMEYAML + "; charset=utf-8"
+
+	// xmlRoot and xmlItem name the root element of an XML response and the elements of its arrays
+	xmlRoot = "response"
+	xmlItem = "item"
+)
+
+// tree turns obj into its JSON representation held as yaml.MapSlice objects, []interface{} arrays and scalars.
+// Going through JSON gives XML and YAML the same field names, omitted fields and values as JSON, and a MapSlice
+// keeps the fields in the order they have in JSON.
+func tree(obj interface{}) (interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return readValue(dec)
+}
+
+func readValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			arr := make([]interface{}, 0)
+			for dec.More() {
+				v, err := readValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+			_, err := dec.Token()
+			return arr, err
+		}
+
+		obj := make(yaml.MapSlice, 0)
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := readValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, yaml.MapItem{Key: key, Value: v})
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	default:
+		return t, nil
+	}
+}
+
+// xmlRender renders the JSON representation of data as XML. Objects become elements named after their fields,
+// the values of arrays become item elements, and null fields are left out. The document element is a response.
+type xmlRender struct {
+	data interface{}
+}
+
+// Render implements render.Render
+func (r xmlRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	t, err := tree(r.data)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	if err := writeElement(enc, xmlRoot, t); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// WriteContentType implements render.Render
+func (r xmlRender) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, xmlContentType)
+}
+
+func writeElement(enc *xml.Encoder, name string, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch t := v.(type) {
+	case yaml.MapSlice:
+		for _, item := range t {
+			if err := writeElement(enc, item.Key.(string), item.Value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range t {
+			if err := writeElement(enc, xmlItem, item); err != nil {
+				return err
+			}
+		}
+	case float64:
+		if err := enc.EncodeToken(xml.CharData(strconv.FormatFloat(t, 'g', -1, 64))); err != nil {
+			return err
+		}
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(t))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// yamlRender renders the JSON representation of data as YAML
+type yamlRender struct {
+	data interface{}
+}
+
+// Render implements render.Render
+func (r yamlRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	t, err := tree(r.data)
+	if err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(t)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// WriteContentType implements render.Render
+func (r yamlRender) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, yamlContentType)
+}
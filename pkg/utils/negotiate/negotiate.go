@@ -0,0 +1,188 @@
+package negotiate
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+const (
+	// MIMEJSON ...
+	MIMEJSON = "application/json"
+
+	// MIMEXML ...
+	MIMEXML = "application/xml"
+
+	// MIMEYAML ...
+	MIMEYAML = "application/yaml"
+
+	// MIMEMsgPack ...
+	MIMEMsgPack = "application/msgpack"
+)
+
+// formatKey labels the negotiated format in gin's context
+const formatKey = "format"
+
+// Formats are the formats responses can be rendered in, in order of preference
+var Formats = []string{MIMEJSON, MIMEXML, MIMEYAML, MIMEMsgPack}
+
+// aliases maps the other media types clients ask for onto the format they name
+var aliases = map[string]string{
+	"text/xml":                MIMEXML,
+	"application/x-yaml":      MIMEYAML,
+	"text/yaml":               MIMEYAML,
+	"application/x-msgpack":   MIMEMsgPack,
+	"application/vnd.msgpack": MIMEMsgPack,
+}
+
+// mediaRange is one of the media ranges of an Accept header, with its quality
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+// Select picks the offered media type the Accept header prefers (RFC 7231, section 5.3.2). Of the offered types
+// the one with the highest quality wins; a type named exactly beats one only matched by a wildcard, and otherwise
+// the earlier offer wins. A missing Accept header accepts anything. ok is false when no offer is acceptable.
+func Select(accept string, offered []string) (mediaType string, ok bool) {
+	if strings.TrimSpace(accept) == "" {
+		if len(offered) == 0 {
+			return "", false
+		}
+		return offered[0], true
+	}
+
+	ranges := parseAccept(accept)
+	bestQ, bestSpecificity := 0.0, -1
+	for _, offer := range offered {
+		q, specificity := quality(ranges, offer)
+		if q > bestQ || (q == bestQ && q > 0 && specificity > bestSpecificity) {
+			mediaType, bestQ, bestSpecificity = offer, q, specificity
+		}
+	}
+	return mediaType, bestQ > 0
+}
+
+// quality finds the quality the most specific range matching the offer gives it, along with how specific that
+// range is: 2 for the exact type, 1 for type/* and 0 for */*. An offer no range matches is not acceptable.
+func quality(ranges []mediaRange, offer string) (float64, int) {
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		s := -1
+		switch {
+		case r.mediaType == offer:
+			s = 2
+		case r.mediaType == "*/*":
+			s = 0
+		case strings.HasSuffix(r.mediaType, "/*") && matchesType(offer, strings.TrimSuffix(r.mediaType, "*")):
+			s = 1
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q, specificity
+}
+
+// matchesType reports whether the offer, or one of its aliases, is of the type ending in prefix, like "text/"
+func matchesType(offer, prefix string) bool {
+	if strings.HasPrefix(offer, prefix) {
+		return true
+	}
+	for alias, mediaType := range aliases {
+		if mediaType == offer && strings.HasPrefix(alias, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAccept splits an Accept header into its media ranges. Aliases are resolved to the format they name
+// and a range with a malformed quality is left out.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+		if alias, ok := aliases[mediaType]; ok {
+			mediaType = alias
+		}
+
+		r := mediaRange{mediaType: mediaType, q: 1}
+		valid := true
+		for _, param := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) != 2 || strings.ToLower(strings.TrimSpace(kv[0])) != "q" {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			r.q = q
+		}
+		if valid {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// SetFormat records the format negotiated for the request in gin's context
+func SetFormat(c *gin.Context, mediaType string) {
+	c.Set(formatKey, mediaType)
+}
+
+// GetFormat returns the format negotiated for the request. When nothing was negotiated the
+// request's Accept header is consulted, falling back to JSON when none of the formats is acceptable.
+func GetFormat(c *gin.Context) string {
+	if f, ok := c.Get(formatKey); ok {
+		return f.(string)
+	}
+	if f, ok := Select(c.GetHeader("Accept"), Formats); ok {
+		return f
+	}
+	return MIMEJSON
+}
+
+// Render writes obj with the given status in the format negotiated for the request, JSON being indented the way
+// c.IndentedJSON does. A request negotiated to a type other than the Formats, like a CSV export, gets JSON.
+func Render(c *gin.Context, status int, obj interface{}) {
+	c.Render(status, renderer(GetFormat(c), obj, true))
+}
+
+// Abort is Render for middleware: it stops the remaining handlers and writes JSON without indenting it
+func Abort(c *gin.Context, status int, obj interface{}) {
+	c.Abort()
+	c.Render(status, renderer(GetFormat(c), obj, false))
+}
+
+func renderer(format string, obj interface{}, indent bool) render.Render {
+	switch format {
+	case MIMEXML:
+		return xmlRender{data: obj}
+	case MIMEYAML:
+		return yamlRender{data: obj}
+	case MIMEMsgPack:
+		return render.MsgPack{Data: obj}
+	}
+	if indent {
+		return render.IndentedJSON{Data: obj}
+	}
+	return render.JSON{Data: obj}
+}
+
+// writeContentType sets the Content-Type of the response unless a handler already did
+func writeContentType(w http.ResponseWriter, contentType string) {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = []string{contentType}
+	}
+}
@@ -0,0 +1,184 @@
+package negotiate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+func TestSelect(t *testing.T) {
+	tests := map[string]struct {
+		accept    string
+		offered   []string
+		mediaType string
+		ok        bool
+	}{
+		"No header":             {accept: "", offered: Formats, mediaType: MIMEJSON, ok: true},
+		"Any":                   {accept: "*/*", offered: Formats, mediaType: MIMEJSON, ok: true},
+		"Exact":                 {accept: "application/xml", offered: Formats, mediaType: MIMEXML, ok: true},
+		"Alias":                 {accept: "application/x-msgpack", offered: Formats, mediaType: MIMEMsgPack, ok: true},
+		"Case insensitive":      {accept: "Text/XML", offered: Formats, mediaType: MIMEXML, ok: true},
+		"Quality":               {accept: "application/json;q=0.5, application/yaml", offered: Formats, mediaType: MIMEYAML, ok: true},
+		"Exact beats wildcard":  {accept: "*/*, application/xml", offered: Formats, mediaType: MIMEXML, ok: true},
+		"Type wildcard":         {accept: "text/*", offered: Formats, mediaType: MIMEXML, ok: true},
+		"Excluded":              {accept: "application/json;q=0, */*", offered: Formats, mediaType: MIMEXML, ok: true},
+		"Malformed quality":     {accept: "application/xml;q=high, application/yaml;q=0.1", offered: Formats, mediaType: MIMEYAML, ok: true},
+		"Route type":            {accept: "text/csv", offered: append(Formats, "text/csv"), mediaType: "text/csv", ok: true},
+		"Unsupported":           {accept: "text/html", offered: Formats, ok: false},
+		"Everything is refused": {accept: "*/*;q=0", offered: Formats, ok: false},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			mediaType, ok := Select(test.accept, test.offered)
+
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.mediaType, mediaType)
+		})
+	}
+}
+
+type testAuthor struct {
+	Name string `json:"name"`
+}
+
+type testArticle struct {
+	ID        string      `json:"id"`
+	Title     string      `json:"title"`
+	Tags      []string    `json:"tags"`
+	AuthorID  *string     `json:"authorId"`
+	Author    *testAuthor `json:"author,omitempty"`
+	Version   int         `json:"version"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+func TestRender(t *testing.T) {
+	obj := testArticle{
+		ID:        "a",
+		Title:     "Fish & <Chips>",
+		Tags:      []string{"go", "sql"},
+		Version:   2,
+		CreatedAt: time.Date(2021, 4, 2, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := map[string]struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		"JSON": {
+			accept:      "application/json",
+			contentType: "application/json; charset=utf-8",
+			body: `{
+    "id": "a",
+    "title": "Fish \u0026 \u003cChips\u003e",
+    "tags": [
+        "go",
+        "sql"
+    ],
+    "authorId": null,
+    "version": 2,
+    "createdAt": "2021-04-02T12:00:00Z"
+}`,
+		},
+		"XML": {
+			accept:      "application/xml",
+			contentType: "application/xml; charset=utf-8",
+			body: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><id>a</id><title>Fish &amp; &lt;Chips&gt;</title><tags><item>go</item><item>sql</item></tags>` +
+				`<version>2</version><createdAt>2021-04-02T12:00:00Z</createdAt></response>`,
+		},
+		"YAML": {
+			accept:      "application/yaml",
+			contentType: "application/yaml; charset=utf-8",
+			body: `id: a
+title: Fish & <Chips>
+tags:
+- go
+- sql
+authorId: null
+version: 2
+createdAt: "2021-04-02T12:00:00Z"
+`,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				Render(c, http.StatusOK, obj)
+			})
+
+			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", test.accept)
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.Equal(t, test.contentType, response.Header().Get("Content-Type"))
+			assert.Equal(t, test.body, response.Body.String())
+		})
+	}
+}
+
+func TestRenderMsgPack(t *testing.T) {
+	response := httptest.NewRecorder()
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		Render(c, http.StatusOK, testArticle{ID: "a", Title: "title", Tags: []string{"go"}, Version: 2})
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/msgpack")
+
+	router.ServeHTTP(response, req)
+
+	assert.Equal(t, "application/msgpack; charset=utf-8", response.Header().Get("Content-Type"))
+
+	var mh codec.MsgpackHandle
+	mh.RawToString = true
+	var decoded map[string]interface{}
+	require.NoError(t, codec.NewDecoderBytes(response.Body.Bytes(), &mh).Decode(&decoded))
+	assert.Equal(t, "title", decoded["title"])
+	assert.Contains(t, decoded, "authorId")
+	assert.NotContains(t, decoded, "author")
+}
+
+func TestRenderNegotiatedFormat(t *testing.T) {
+	tests := map[string]struct {
+		format      string
+		contentType string
+	}{
+		"Negotiated format wins over the header": {format: MIMEYAML, contentType: "application/yaml; charset=utf-8"},
+		"Route type falls back to JSON":          {format: "text/csv", contentType: "application/json; charset=utf-8"},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				SetFormat(c, test.format)
+				Render(c, http.StatusOK, testAuthor{Name: "name"})
+			})
+
+			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", "application/xml")
+
+			router.ServeHTTP(response, req)
+
+			assert.Equal(t, test.contentType, response.Header().Get("Content-Type"))
+		})
+	}
+}
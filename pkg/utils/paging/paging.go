@@ -0,0 +1,29 @@
+package paging
+
+import (
+	"github.com/kott/go-service-example/pkg/errors"
+)
+
+// MaxLimit is the largest page the server hands out, larger limits are capped to it
+const MaxLimit = 100
+
+// Query holds the offset based paging parameters of list endpoints
+type Query struct {
+	Limit  int `form:"limit,default=25"`
+	Offset int `form:"offset,default=0"`
+}
+
+// Validate rejects negative or empty pages and caps the limit to MaxLimit
+func (q *Query) Validate() []errors.AppError {
+	var errs []errors.AppError
+	if q.Limit < 1 {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "limit must be a positive number", Field: "limit"})
+	}
+	if q.Offset < 0 {
+		errs = append(errs, errors.AppError{Code: errors.BadRequest, Description: "offset must not be negative", Field: "offset"})
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+	return errs
+}
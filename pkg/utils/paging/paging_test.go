@@ -0,0 +1,43 @@
+package paging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kott/go-service-example/pkg/errors"
+)
+
+func TestQueryValidate(t *testing.T) {
+	tests := map[string]struct {
+		query  Query
+		expect Query
+		errs   []errors.AppError
+	}{
+		"Valid": {
+			query:  Query{Limit: 25, Offset: 50},
+			expect: Query{Limit: 25, Offset: 50},
+		},
+		"Capped limit": {
+			query:  Query{Limit: 500},
+			expect: Query{Limit: MaxLimit},
+		},
+		"Empty page": {
+			query:  Query{Limit: 0, Offset: -1},
+			expect: Query{Limit: 0, Offset: -1},
+			errs: []errors.AppError{
+				{Code: errors.BadRequest, Description: "limit must be a positive number", Field: "limit"},
+				{Code: errors.BadRequest, Description: "offset must not be negative", Field: "offset"},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			errs := test.query.Validate()
+
+			assert.Equal(t, test.errs, errs)
+			assert.Equal(t, test.expect, test.query)
+		})
+	}
+}